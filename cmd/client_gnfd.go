@@ -51,8 +51,7 @@ func NewClient(ctx *cli.Context, opts ClientOptions) (client.IClient, error) {
 
 		account, err = types.NewAccountFromPrivateKey("mechain-account", privateKey)
 		if err != nil {
-			fmt.Println("new account err", err.Error())
-			return nil, err
+			return nil, fmt.Errorf("new account err: %v", err)
 		}
 	}
 
@@ -68,8 +67,7 @@ func NewClient(ctx *cli.Context, opts ClientOptions) (client.IClient, error) {
 	}
 
 	if err != nil {
-		fmt.Printf("failed to create client %s \n", err.Error())
-		return nil, err
+		return nil, fmt.Errorf("failed to create client: %v", err)
 	}
 
 	return cli, nil
@@ -121,10 +119,10 @@ func waitTxnStatus(cli client.IClient, ctx context.Context, txnHash string, txnI
 
 	txnResponse, err := cli.WaitForTx(ctxTimeout, txnHash)
 	if err != nil {
-		return fmt.Errorf("the %s txn: %s ,has been submitted, please check it later:%v", txnInfo, txnHash, err)
+		return newCmdError(ErrTxTimeout, fmt.Errorf("the %s txn: %s ,has been submitted, please check it later:%v", txnInfo, txnHash, err))
 	}
 	if txnResponse.TxResult.Code != 0 {
-		return fmt.Errorf("the %s txn: %s has failed with response code: %d", txnInfo, txnHash, txnResponse.TxResult.Code)
+		return newCmdError(ErrTxFailed, fmt.Errorf("the %s txn: %s has failed with response code: %d", txnInfo, txnHash, txnResponse.TxResult.Code))
 	}
 
 	return nil
@@ -143,6 +143,9 @@ func getAccountBalance(ctx *cli.Context) error {
 	if err != nil {
 		return toCmdErr(err)
 	}
+	if isStructuredOutput() {
+		return printOutput(balanceOutput{Address: addr, Balance: resp.Amount.String(), Denom: types.Denom})
+	}
 	fmt.Printf("balance: %s wei%s\n", resp.Amount.String(), types.Denom)
 	return nil
 }
@@ -271,7 +274,9 @@ func importKey(ctx *cli.Context) error {
 	}
 
 	// if it is the first keystore, set it as the default key
-	checkAndWriteDefaultKey(homeDir, convertAddressToLower(key.Address.String()))
+	if err = checkAndWriteDefaultKey(homeDir, convertAddressToLower(key.Address.String())); err != nil {
+		return toCmdErr(err)
+	}
 
 	fmt.Printf("imported account: %s, keystore: %s \n", key.Address, keyFilePath)
 	return nil
@@ -302,15 +307,35 @@ func listAccounts(ctx *cli.Context) error {
 }
 
 func listKeyStore(keystoreDir, defaultAccount string) error {
-	var (
-		keyFileContent []byte
-		err            error
-	)
+	accounts, err := loadKeyStoreAccounts(keystoreDir)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		if defaultAccount != "" && convertAddressToLower(account.address) == defaultAccount {
+			fmt.Printf("Account: { %s },  Keystore : %s (default account)\n", account.address, account.keyPath)
+		} else {
+			fmt.Printf("Account: { %s },  Keystore : %s \n", account.address, account.keyPath)
+		}
+	}
+	return nil
+}
+
+// keyStoreAccount is the address and the key file of an account in the keystore directory
+type keyStoreAccount struct {
+	address string
+	keyPath string
+}
+
+// loadKeyStoreAccounts read the accounts of the key files in the keystore directory
+func loadKeyStoreAccounts(keystoreDir string) ([]keyStoreAccount, error) {
 	files, err := os.ReadDir(keystoreDir)
 	if err != nil {
-		return errors.New("keystore not exists")
+		return nil, errors.New("keystore not exists")
 	}
 
+	accounts := make([]keyStoreAccount, 0, len(files))
 	for _, file := range files {
 		if !file.IsDir() {
 			// if it is not a valid key file name , bypass it
@@ -318,24 +343,19 @@ func listKeyStore(keystoreDir, defaultAccount string) error {
 				continue
 			}
 			keyPath := filepath.Join(keystoreDir, file.Name())
-			keyFileContent, err = os.ReadFile(keyPath)
+			keyFileContent, err := os.ReadFile(keyPath)
 			if err != nil {
-				return fmt.Errorf("failed to read the keyfile at '%s': %v", keyPath, err)
+				return nil, fmt.Errorf("failed to read the keyfile at '%s': %v", keyPath, err)
 			}
 
 			k := new(encryptedKey)
 			if err = json.Unmarshal(keyFileContent, k); err != nil {
-				return toCmdErr(err)
-			}
-
-			if defaultAccount != "" && convertAddressToLower(k.Address) == defaultAccount {
-				fmt.Printf("Account: { %s },  Keystore : %s (default account)\n", k.Address, keyPath)
-			} else {
-				fmt.Printf("Account: { %s },  Keystore : %s \n", k.Address, keyPath)
+				return nil, toCmdErr(err)
 			}
+			accounts = append(accounts, keyStoreAccount{address: k.Address, keyPath: keyPath})
 		}
 	}
-	return nil
+	return accounts, nil
 }
 
 func exportAccount(ctx *cli.Context) error {
@@ -425,7 +445,9 @@ func createAccount(ctx *cli.Context) error {
 	}
 
 	// if it is the first keystore, set it as the default key
-	checkAndWriteDefaultKey(homeDir, convertAddressToLower(key.Address.String()))
+	if err = checkAndWriteDefaultKey(homeDir, convertAddressToLower(key.Address.String())); err != nil {
+		return toCmdErr(err)
+	}
 
 	fmt.Printf("created new account: {%s}, keystore: %s \n", account.GetAddress(), keyFilePath)
 	return nil
@@ -448,7 +470,7 @@ func Bridge(ctx *cli.Context) error {
 	amountStr := ctx.String(amountFlag)
 	amount, ok := math.NewIntFromString(amountStr)
 	if !ok {
-		return toCmdErr(fmt.Errorf("%s is not valid amount", amount))
+		return toCmdErr(invalidArgsError("%s is not valid amount", amount))
 	}
 	txResp, err := client.TransferOut(c, toAddr, amount, types.TxOption{})
 	if err != nil {
@@ -481,7 +503,7 @@ func Transfer(ctx *cli.Context) error {
 	amountStr := ctx.String(amountFlag)
 	amount, ok := math.NewIntFromString(amountStr)
 	if !ok {
-		return toCmdErr(fmt.Errorf("%s is not valid amount", amount))
+		return toCmdErr(invalidArgsError("%s is not valid amount", amount))
 	}
 	txHash, err := client.Transfer(c, toAddr, amount, types.TxOption{})
 	if err != nil {
@@ -498,7 +520,7 @@ func Transfer(ctx *cli.Context) error {
 
 func setDefaultAccount(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number error"))
+		return toCmdErr(invalidArgsError("args number error"))
 	}
 
 	defaultAddress := ctx.Args().Get(0)
@@ -551,35 +573,32 @@ func parseKeystore(ctx *cli.Context) (string, string, error) {
 	return privateKey, keyFile, nil
 }
 
-func checkAndWriteDefaultKey(homeDir string, content string) {
-	var err error
+func checkAndWriteDefaultKey(homeDir string, content string) error {
 	filePath := filepath.Join(homeDir, DefaultAccountPath)
-	_, err = os.Stat(filePath)
+	_, err := os.Stat(filePath)
 	if os.IsNotExist(err) {
 		if err = os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
-			fmt.Printf("failed to create directory %s, error: %v\n", filepath.Dir(filePath), err)
+			return fmt.Errorf("failed to create directory %s, error: %v", filepath.Dir(filePath), err)
 		}
 
-		err = os.WriteFile(filePath, []byte(content), 0o644)
-		if err != nil {
-			fmt.Printf("failed to write default keystore info %v \n", err)
-			return
-		}
-	} else {
-		// file exist, check if it is empty
-		fileContent, readErr := os.ReadFile(filePath)
-		if readErr != nil {
-			fmt.Printf("read default keystore info fail %v \n", readErr)
-			return
+		if err = os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write default keystore info %v", err)
 		}
+		return nil
+	}
 
-		if len(fileContent) == 0 {
-			err = os.WriteFile(filePath, []byte(content), 0o644)
-			if err != nil {
-				fmt.Printf("failed to write default keystore info %v \n", err)
-			}
+	// file exist, check if it is empty
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("read default keystore info fail %v", err)
+	}
+
+	if len(fileContent) == 0 {
+		if err = os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write default keystore info %v", err)
 		}
 	}
+	return nil
 }
 
 func isKeystoreExist(keystoreDir string, address string) bool {
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
 
 	"cosmossdk.io/math"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -14,6 +13,7 @@ import (
 	"github.com/evmos/evmos/v12/sdk/types"
 	mechaindTypes "github.com/evmos/evmos/v12/types"
 	storagetypes "github.com/evmos/evmos/v12/x/storage/types"
+	"github.com/zkMeLabs/mechain-go-sdk/client"
 	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
 )
 
@@ -350,8 +350,7 @@ func updateBucket(ctx *cli.Context) error {
 	opts.TxOpts = &TxnOptionWithSyncMode
 	txnHash, err := client.UpdateBucketInfo(c, bucketName, opts)
 	if err != nil {
-		fmt.Println("update bucket error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	err = waitTxnStatus(client, c, txnHash, "UpdateBucket")
@@ -361,8 +360,7 @@ func updateBucket(ctx *cli.Context) error {
 
 	bucketInfo, err := client.HeadBucket(c, bucketName)
 	if err != nil {
-		// head fail, no need to print the error
-		return nil
+		return toCmdErr(err)
 	}
 
 	fmt.Printf("latest bucket meta on chain:\nvisibility:%s\nread quota:%d\npayment address:%s \n", bucketInfo.GetVisibility().String(),
@@ -398,8 +396,7 @@ func migrateBucket(ctx *cli.Context) error {
 
 	txnHash, err := client.MigrateBucket(c, bucketName, uint32(dstPrimarySPID), opts)
 	if err != nil {
-		fmt.Println("migrate bucket error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	err = waitTxnStatus(client, c, txnHash, "MigrateBucket")
@@ -409,8 +406,7 @@ func migrateBucket(ctx *cli.Context) error {
 
 	bucketInfo, err := client.HeadBucket(c, bucketName)
 	if err != nil {
-		// head fail, no need to print the error
-		return nil
+		return toCmdErr(err)
 	}
 
 	fmt.Printf("latest bucket meta on chain:\nvisibility:%s\nread quota:%d\npayment address:%s \n", bucketInfo.GetVisibility().String(),
@@ -429,19 +425,37 @@ func listBuckets(ctx *cli.Context) error {
 	c, cancelCreateBucket := context.WithCancel(globalContext)
 	defer cancelCreateBucket()
 
-	spInfo, err := client.ListStorageProviders(c, true)
-	if err != nil {
-		fmt.Println("fail to get SP info to list bucket:", err.Error())
-		return nil
-	}
-
-	bucketListRes, err := client.ListBuckets(c, sdktypes.ListBucketsOptions{
-		ShowRemovedBucket: false,
-		Endpoint:          spInfo[0].Endpoint,
-	})
+	bucketListRes, err := listAccountBuckets(client, c)
 	if err != nil {
 		return toCmdErr(err)
 	}
+	// the bucket names are completed by the shell completion as well
+	bucketNames := make([]string, 0, len(bucketListRes.Buckets))
+	for _, bucket := range bucketListRes.Buckets {
+		if !bucket.Removed {
+			bucketNames = append(bucketNames, bucket.BucketInfo.BucketName)
+		}
+	}
+	saveCompletionCache(ctx, bucketsCompletionKey(ctx), bucketNames)
+
+	if isStructuredOutput() {
+		output := make([]bucketOutput, 0, len(bucketListRes.Buckets))
+		for _, bucket := range bucketListRes.Buckets {
+			if bucket.Removed {
+				continue
+			}
+			info := bucket.BucketInfo
+			output = append(output, bucketOutput{
+				BucketName: info.BucketName,
+				BucketID:   info.Id.String(),
+				Owner:      info.Owner,
+				Visibility: info.Visibility.String(),
+				Status:     info.BucketStatus.String(),
+				CreateTime: formatOutputTime(info.CreateAt),
+			})
+		}
+		return printOutput(output)
+	}
 
 	if len(bucketListRes.Buckets) == 0 {
 		return nil
@@ -449,16 +463,26 @@ func listBuckets(ctx *cli.Context) error {
 
 	for _, bucket := range bucketListRes.Buckets {
 		info := bucket.BucketInfo
-
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(info.CreateAt, 0).In(location)
 		if !bucket.Removed {
-			fmt.Printf("%s  %s\n", t.Format(iso8601DateFormat), info.BucketName)
+			fmt.Printf("%-*s  %s\n", timeColumnWidth(), formatUnixTime(info.CreateAt), info.BucketName)
 		}
 	}
 	return nil
 }
 
+// listAccountBuckets list the buckets of the default account from the first SP in service
+func listAccountBuckets(cli client.IClient, c context.Context) (sdktypes.ListBucketsResult, error) {
+	spInfo, err := cli.ListStorageProviders(c, true)
+	if err != nil {
+		return sdktypes.ListBucketsResult{}, fmt.Errorf("fail to get SP info to list bucket: %v", err)
+	}
+
+	return cli.ListBuckets(c, sdktypes.ListBucketsOptions{
+		ShowRemovedBucket: false,
+		Endpoint:          spInfo[0].Endpoint,
+	})
+}
+
 func mirrorBucket(ctx *cli.Context) error {
 	client, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
 	if err != nil {
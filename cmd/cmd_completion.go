@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/zkMeLabs/mechain-go-sdk/client"
+	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
+)
+
+// the shells supported by the completion command
+const (
+	shellBash = "bash"
+	shellZsh  = "zsh"
+	shellFish = "fish"
+)
+
+const (
+	// DefaultCompletionCachePath is the cache of the buckets and the objects listed by the completion under the home directory
+	DefaultCompletionCachePath = "completion/cache.json"
+	// completionCacheTTL is how long the listed buckets and objects are reused, so that pressing tab repeatedly stays responsive
+	completionCacheTTL = 30 * time.Second
+	// completionCacheMaxAge is how long the expired results are kept, they are completed if the results can't be listed
+	completionCacheMaxAge = 24 * time.Hour
+	// completionTimeout is the timeout of listing the buckets or the objects for the completion
+	completionTimeout  = 5 * time.Second
+	maxCompletionItems = 200
+)
+
+// the completion scripts pass the word under the cursor to the command before the --generate-bash-completion flag,
+// so that the urls can be completed by the typed prefix
+const bashCompletionScript = `# bash completion for mechain-cmd
+_mechain_cmd_complete() {
+  local line="${COMP_LINE:0:COMP_POINT}"
+  local cur="${line##*[[:space:]]}"
+  local -a words
+  read -r -a words <<< "${line%"$cur"}"
+  local opts
+  opts=$("${words[@]}" "$cur" --generate-bash-completion 2>/dev/null)
+  local IFS=$'\n'
+  COMPREPLY=($(compgen -W "$opts" -- "$cur"))
+  # the folders and the buckets are completed further, so no space is added after them
+  if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == */ ]]; then
+    compopt -o nospace
+  fi
+  # bash splits the words by the colon of the urls, so the part before the last colon is removed from the replies
+  if [[ "$cur" == *:* && "$COMP_WORDBREAKS" == *:* ]]; then
+    local colon_prefix="${cur%"${cur##*:}"}"
+    local i
+    for i in "${!COMPREPLY[@]}"; do
+      COMPREPLY[i]="${COMPREPLY[i]#"$colon_prefix"}"
+    done
+  fi
+}
+# the file paths are completed if there is no candidate
+complete -o default -F _mechain_cmd_complete mechain-cmd
+`
+
+const zshCompletionScript = `#compdef mechain-cmd
+# zsh completion for mechain-cmd
+_mechain_cmd() {
+  local -a opts dirs values
+  opts=("${(@f)$(${words[1,CURRENT-1]} "${words[CURRENT]}" --generate-bash-completion 2>/dev/null)}")
+  opts=(${opts:#})
+  # the file paths are completed if there is no candidate
+  if (( ${#opts} == 0 )); then
+    _files
+    return
+  fi
+  # the folders and the buckets are completed further, so no space is added after them
+  dirs=(${(M)opts:#*/})
+  values=(${opts:#*/})
+  # the colon of the urls is escaped, since _describe takes the part after the colon as the description
+  values=("${(@)values/#mechain:/mechain\\:}")
+  compadd -S '' -- $dirs
+  _describe 'values' values
+}
+compdef _mechain_cmd mechain-cmd
+`
+
+const fishCompletionScript = `# fish completion for mechain-cmd
+function __mechain_cmd_complete
+    set -l tokens (commandline -opc)
+    set -l cur (commandline -ct)
+    $tokens "$cur" --generate-bash-completion 2>/dev/null
+end
+# the file paths are completed as well, since the files are uploaded by the object commands
+complete -c mechain-cmd -a '(__mechain_cmd_complete)'
+`
+
+// cmdCompletion return the command to print the shell completion script
+func cmdCompletion() *cli.Command {
+	return &cli.Command{
+		Name:      "completion",
+		Action:    printCompletionScript,
+		Usage:     "print the shell completion script of bash, zsh or fish",
+		ArgsUsage: "bash|zsh|fish",
+		Description: `
+Print the completion script of the shell. The script completes the commands, the flags, the bucket names and the object
+keys of the mechain:// urls, the keystore addresses of "account set-default" and the task ids of --taskId.
+
+The buckets and the objects are listed with the default account, and the password can't be prompted when completing,
+so they are listed only if the password file is set by --passwordfile. Otherwise the buckets shown by the last
+"bucket ls" are completed. The listed results are cached for 30 seconds.
+
+Examples:
+# enable the completion in the current bash session, add it to ~/.bashrc to enable it for the new sessions
+$ source <(mechain-cmd completion bash)
+# enable the completion in zsh, add it to ~/.zshrc after compinit
+$ source <(mechain-cmd completion zsh)
+# install the completion of fish
+$ mechain-cmd completion fish > ~/.config/fish/completions/mechain-cmd.fish`,
+		BashComplete: func(ctx *cli.Context) {
+			for _, shell := range []string{shellBash, shellZsh, shellFish} {
+				fmt.Println(shell)
+			}
+		},
+	}
+}
+
+func printCompletionScript(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return toCmdErr(invalidArgsError("args number should be one, it is the shell: bash, zsh or fish"))
+	}
+
+	switch shell := ctx.Args().First(); shell {
+	case shellBash:
+		fmt.Print(bashCompletionScript)
+	case shellZsh:
+		fmt.Print(zshCompletionScript)
+	case shellFish:
+		fmt.Print(fishCompletionScript)
+	default:
+		return toCmdErr(invalidArgsError("unsupported shell %s, it should be bash, zsh or fish", shell))
+	}
+	return nil
+}
+
+// setupCompletion set the completion of the args of the leaf commands: the keystore addresses of "account set-default",
+// the task ids of --taskId, and the mechain:// urls of the commands taking them. The bucket commands complete the
+// bucket names only, and the others complete the object keys as well
+func setupCompletion(commands []*cli.Command, parentName string) {
+	for _, command := range commands {
+		if len(command.Subcommands) > 0 {
+			setupCompletion(command.Subcommands, command.Name)
+			continue
+		}
+		if command.BashComplete != nil {
+			continue
+		}
+
+		switch {
+		case parentName == "account" && command.Name == "set-default":
+			command.BashComplete = completeArgs(func(ctx *cli.Context, prev, cur string) []string {
+				return completeAccounts(ctx)
+			})
+		case hasFlag(command, taskIDFlag):
+			command.BashComplete = completeArgs(func(ctx *cli.Context, prev, cur string) []string {
+				if prev != "--"+taskIDFlag {
+					return nil
+				}
+				return completeTaskIDs(ctx)
+			})
+		case strings.Contains(command.ArgsUsage, "URL") || strings.Contains(command.ArgsUsage, urlPrefix) || command.Name == "sync":
+			withObjects := parentName != "bucket"
+			command.BashComplete = completeArgs(func(ctx *cli.Context, prev, cur string) []string {
+				return completeURLs(ctx, cur, withObjects)
+			})
+		}
+	}
+}
+
+func hasFlag(command *cli.Command, name string) bool {
+	for _, flag := range command.Flags {
+		for _, flagName := range flag.Names() {
+			if flagName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// completeArgs return the completion of the command, the flags are completed if the word under the cursor starts with "-",
+// otherwise the candidates of the args are printed one per line
+func completeArgs(complete func(ctx *cli.Context, prev, cur string) []string) cli.BashCompleteFunc {
+	return func(ctx *cli.Context) {
+		prev, cur := completionWords()
+		if strings.HasPrefix(cur, "-") {
+			cli.DefaultCompleteWithFlags(ctx.Command)(ctx)
+			return
+		}
+		for _, candidate := range complete(ctx, prev, cur) {
+			fmt.Println(candidate)
+		}
+	}
+}
+
+// completionWords return the word before the cursor and the word under the cursor, which are the last args
+// before the --generate-bash-completion flag
+func completionWords() (string, string) {
+	args := os.Args[1:]
+	if len(args) > 0 && args[len(args)-1] == "--generate-bash-completion" {
+		args = args[:len(args)-1]
+	}
+	switch len(args) {
+	case 0:
+		return "", ""
+	case 1:
+		return "", args[0]
+	}
+	return args[len(args)-2], args[len(args)-1]
+}
+
+// completeURLs complete the url prefix, the buckets of the account, and the objects and the folders under the typed prefix
+func completeURLs(ctx *cli.Context, cur string, withObjects bool) []string {
+	if !strings.HasPrefix(cur, urlPrefix) {
+		if !strings.HasPrefix(urlPrefix, cur) {
+			return nil
+		}
+		cur = urlPrefix
+	}
+
+	bucketName, prefixName, found := strings.Cut(strings.TrimPrefix(cur, urlPrefix), "/")
+	if !found || !withObjects {
+		bucketNames := cachedCompletion(ctx, bucketsCompletionKey(ctx), func(cli client.IClient, c context.Context) ([]string, error) {
+			bucketListRes, err := listAccountBuckets(cli, c)
+			if err != nil {
+				return nil, err
+			}
+			bucketNames := make([]string, 0, len(bucketListRes.Buckets))
+			for _, bucket := range bucketListRes.Buckets {
+				if !bucket.Removed {
+					bucketNames = append(bucketNames, bucket.BucketInfo.BucketName)
+				}
+			}
+			return bucketNames, nil
+		})
+		candidates := make([]string, 0, len(bucketNames))
+		for _, name := range bucketNames {
+			if withObjects {
+				name += "/"
+			}
+			candidates = append(candidates, urlPrefix+name)
+		}
+		return candidates
+	}
+
+	// the objects and the folders are listed by the typed prefix with the delimiter, so only one level is completed
+	key := fmt.Sprintf("objects/%s/%s", bucketName, prefixName)
+	names := cachedCompletion(ctx, key, func(cli client.IClient, c context.Context) ([]string, error) {
+		listResult, err := cli.ListObjects(c, bucketName, sdktypes.ListObjectsOptions{
+			ShowRemovedObject: false,
+			Delimiter:         "/",
+			MaxKeys:           maxCompletionItems,
+			Prefix:            prefixName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(listResult.Objects)+len(listResult.CommonPrefixes))
+		for _, object := range listResult.Objects {
+			names = append(names, object.ObjectInfo.ObjectName)
+		}
+		return append(names, listResult.CommonPrefixes...), nil
+	})
+	candidates := make([]string, 0, len(names))
+	for _, name := range names {
+		candidates = append(candidates, urlPrefix+bucketName+"/"+name)
+	}
+	return candidates
+}
+
+// completeAccounts complete the addresses of the accounts in the keystore directory
+func completeAccounts(ctx *cli.Context) []string {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return nil
+	}
+	accounts, err := loadKeyStoreAccounts(filepath.Join(homeDir, DefaultKeyDir))
+	if err != nil {
+		return nil
+	}
+	addresses := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		addresses = append(addresses, account.address)
+	}
+	return addresses
+}
+
+// completeTaskIDs complete the ids of the tasks which have the state file under the home directory
+func completeTaskIDs(ctx *cli.Context) []string {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(homeDir)
+	if err != nil {
+		return nil
+	}
+	taskIDs := make([]string, 0)
+	for _, entry := range entries {
+		taskID := strings.TrimPrefix(entry.Name(), ".")
+		if entry.IsDir() && taskID != entry.Name() && fileExists(getTaskFilePath(homeDir, taskID)) {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	return taskIDs
+}
+
+// completionCacheEntry is the results of a completion and the unix time when they were listed
+type completionCacheEntry struct {
+	Time   int64    `json:"time"`
+	Values []string `json:"values"`
+}
+
+// bucketsCompletionKey return the cache key of the buckets of the default account
+func bucketsCompletionKey(ctx *cli.Context) string {
+	address, err := getUserAddress(ctx)
+	if err != nil {
+		address = ""
+	}
+	return "buckets/" + convertAddressToLower(address)
+}
+
+// cachedCompletion return the results of the key which are listed within completionCacheTTL, or list them again.
+// The expired results are returned if they can't be listed, since the password may not be available when completing
+func cachedCompletion(ctx *cli.Context, key string, list func(cli client.IClient, c context.Context) ([]string, error)) []string {
+	entry, ok := loadCompletionCache(ctx)[key]
+	if ok && time.Since(time.Unix(entry.Time, 0)) < completionCacheTTL {
+		return entry.Values
+	}
+
+	gnfdClient, err := newCompletionClient(ctx)
+	if err != nil {
+		return entry.Values
+	}
+	c, cancelComplete := context.WithTimeout(globalContext, completionTimeout)
+	defer cancelComplete()
+	values, err := list(gnfdClient, c)
+	if err != nil {
+		return entry.Values
+	}
+	saveCompletionCache(ctx, key, values)
+	return values
+}
+
+// newCompletionClient create the client of the default account to list the buckets and the objects. The password can't
+// be prompted when completing, so the password file should be set, and the default config file is not generated
+func newCompletionClient(ctx *cli.Context) (client.IClient, error) {
+	if ctx.String(passwordFileFlag) == "" {
+		return nil, errors.New("the password file is not set")
+	}
+	if ctx.String(rpcAddrConfigField) == "" {
+		config, err := loadConfigIfExist(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, errors.New("the config file does not exist")
+		}
+	}
+	return NewClient(ctx, ClientOptions{IsQueryCmd: false})
+}
+
+func loadCompletionCache(ctx *cli.Context) map[string]completionCacheEntry {
+	cache := make(map[string]completionCacheEntry)
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return cache
+	}
+	content, err := os.ReadFile(filepath.Join(homeDir, DefaultCompletionCachePath))
+	if err != nil {
+		return cache
+	}
+	// the broken cache is ignored and overwritten
+	_ = json.Unmarshal(content, &cache)
+	return cache
+}
+
+// saveCompletionCache write the results of the key to the cache and drop the results older than completionCacheMaxAge,
+// the cache is best effort so the errors are ignored
+func saveCompletionCache(ctx *cli.Context, key string, values []string) {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return
+	}
+	cache := loadCompletionCache(ctx)
+	now := time.Now()
+	for cacheKey, entry := range cache {
+		if now.Sub(time.Unix(entry.Time, 0)) > completionCacheMaxAge {
+			delete(cache, cacheKey)
+		}
+	}
+	sort.Strings(values)
+	cache[key] = completionCacheEntry{Time: now.Unix(), Values: values}
+
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(filepath.Join(homeDir, DefaultCompletionCachePath), content)
+}
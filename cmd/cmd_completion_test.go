@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_completionWords(t *testing.T) {
+	defer func(args []string) { os.Args = args }(os.Args)
+
+	tests := []struct {
+		args     []string
+		wantPrev string
+		wantCur  string
+	}{
+		{args: []string{"mechain-cmd", "--generate-bash-completion"}, wantPrev: "", wantCur: ""},
+		{args: []string{"mechain-cmd", "obj", "--generate-bash-completion"}, wantPrev: "", wantCur: "obj"},
+		{args: []string{"mechain-cmd", "object", "get", "", "--generate-bash-completion"}, wantPrev: "get", wantCur: ""},
+		{args: []string{"mechain-cmd", "object", "get", "mechain://bucket/a", "--generate-bash-completion"}, wantPrev: "get", wantCur: "mechain://bucket/a"},
+		{args: []string{"mechain-cmd", "task", "status", "--taskId", "", "--generate-bash-completion"}, wantPrev: "--taskId", wantCur: ""},
+	}
+	for _, tt := range tests {
+		os.Args = tt.args
+		if prev, cur := completionWords(); prev != tt.wantPrev || cur != tt.wantCur {
+			t.Errorf("completionWords() with %q = %q, %q, want %q, %q", tt.args, prev, cur, tt.wantPrev, tt.wantCur)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
+	"github.com/zkMeLabs/mechain-go-sdk/client"
+	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
+)
+
+// copyTask indicates the source object and the destination of copying or moving an object
+type copyTask struct {
+	srcInfo    *storageTypes.ObjectInfo
+	dstBucket  string
+	dstObject  string
+	resultItem *transferResult
+}
+
+// cmdCopyObject return the command to copy objects between buckets or inside a bucket
+func cmdCopyObject() *cli.Command {
+	return &cli.Command{
+		Name:      "cp",
+		Action:    copyObject,
+		Usage:     "copy an object or the objects under a prefix to another location",
+		ArgsUsage: "SOURCE-OBJECT-URL DESTINATION-OBJECT-URL",
+		Description: `
+Copy an object inside a bucket or across buckets. The payload is streamed from the source object to the destination
+without a local copy, and the content type, tags and visibility of the source object are kept.
+If the destination ends with "/" or only contains the bucket name, the base name of the source object is used as the object name.
+
+Examples:
+# copy an object to another bucket
+$ mechain-cmd object cp mechain://mechain-bucket/mechain-object mechain://backup-bucket/mechain-object
+# copy the objects under the prefix to the prefix of another bucket
+$ mechain-cmd object cp --recursive mechain://mechain-bucket/prefix/ mechain://backup-bucket/prefix/`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  recursiveFlag,
+				Value: false,
+				Usage: "performed on all the objects under the specified prefix in a recursive way",
+			},
+			&cli.Uint64Flag{
+				Name: partSizeFlag,
+				// the default part size is 32M
+				Value: 32 * 1024 * 1024,
+				Usage: "indicate the resumable upload 's part size, uploading a large file in multiple parts. " +
+					"The part size is an integer multiple of the segment size.",
+			},
+			&cli.BoolFlag{
+				Name:  bypassSealFlag,
+				Value: false,
+				Usage: "if set this flag as true, it will not wait for the objects to be sealed after the copying is completed.",
+			},
+			dryRunCliFlag(),
+			yesCliFlag(),
+		},
+	}
+}
+
+// cmdMoveObject return the command to rename objects inside a bucket or move them to another bucket
+func cmdMoveObject() *cli.Command {
+	return &cli.Command{
+		Name:      "mv",
+		Action:    moveObject,
+		Usage:     "move or rename an object or the objects under a prefix",
+		ArgsUsage: "SOURCE-OBJECT-URL DESTINATION-OBJECT-URL",
+		Description: `
+Move an object inside a bucket or across buckets. The object is copied to the destination firstly in the same way as "object cp",
+the source object is deleted only after the destination object has been sealed.
+
+Examples:
+# rename an object
+$ mechain-cmd object mv mechain://mechain-bucket/old-name mechain://mechain-bucket/new-name
+# move the objects under the prefix to another prefix
+$ mechain-cmd object mv --recursive mechain://mechain-bucket/old-prefix/ mechain://mechain-bucket/new-prefix/`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  recursiveFlag,
+				Value: false,
+				Usage: "performed on all the objects under the specified prefix in a recursive way",
+			},
+			&cli.Uint64Flag{
+				Name: partSizeFlag,
+				// the default part size is 32M
+				Value: 32 * 1024 * 1024,
+				Usage: "indicate the resumable upload 's part size, uploading a large file in multiple parts. " +
+					"The part size is an integer multiple of the segment size.",
+			},
+			dryRunCliFlag(),
+			yesCliFlag(),
+		},
+	}
+}
+
+func copyObject(ctx *cli.Context) error {
+	return copyOrMoveObjects(ctx, false)
+}
+
+func moveObject(ctx *cli.Context) error {
+	return copyOrMoveObjects(ctx, true)
+}
+
+// copyOrMoveObjects copy the objects to the destination, and delete the source objects if isMove is true
+func copyOrMoveObjects(ctx *cli.Context, isMove bool) error {
+	if ctx.NArg() != 2 {
+		return toCmdErr(invalidArgsError("args number should be 2"))
+	}
+
+	srcUrl, dstUrl := ctx.Args().Get(0), ctx.Args().Get(1)
+	if !isObjectUrl(srcUrl) || !isObjectUrl(dstUrl) {
+		return toCmdErr(invalidArgsError("both the source and the destination should be OBJECT-URL"))
+	}
+
+	srcBucket, srcName, err := ParseBucketAndPrefix(srcUrl)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	dstBucket, dstName, err := ParseBucketAndPrefix(dstUrl)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	c, cancelCopy := context.WithCancel(globalContext)
+	defer cancelCopy()
+
+	if _, err = gnfdClient.HeadBucket(c, dstBucket); err != nil {
+		return toCmdErr(ErrBucketNotExist)
+	}
+
+	tasks := make([]*copyTask, 0)
+	if ctx.Bool(recursiveFlag) {
+		if srcName != "" && !strings.HasSuffix(srcName, "/") {
+			srcName += "/"
+		}
+		if dstName != "" && !strings.HasSuffix(dstName, "/") {
+			dstName += "/"
+		}
+		if srcBucket == dstBucket && strings.HasPrefix(dstName, srcName) {
+			return toCmdErr(invalidArgsError("the destination prefix should not be inside the source prefix"))
+		}
+
+		err = walkObjectsByPage(gnfdClient, c, srcBucket, srcName, true, func(listResult sdktypes.ListObjectsResult) error {
+			for _, object := range listResult.Objects {
+				relName := strings.TrimPrefix(object.ObjectInfo.ObjectName, srcName)
+				if object.Removed || relName == "" {
+					continue
+				}
+				tasks = append(tasks, newCopyTask(object.ObjectInfo, dstBucket, dstName+relName))
+			}
+			return nil
+		})
+		if err != nil {
+			return toCmdErr(err)
+		}
+	} else {
+		if srcName == "" {
+			return toCmdErr(invalidArgsError("fail to parse the source object name"))
+		}
+		objectDetail, err := gnfdClient.HeadObject(c, srcBucket, srcName)
+		if err != nil {
+			return toCmdErr(ErrObjectNotExist)
+		}
+		if dstName == "" || strings.HasSuffix(dstName, "/") {
+			dstName += path.Base(srcName)
+		}
+		// the folder object is copied as a folder, path.Base drops its trailing slash
+		if strings.HasSuffix(srcName, "/") && !strings.HasSuffix(dstName, "/") {
+			dstName += "/"
+		}
+		if srcBucket == dstBucket && srcName == dstName {
+			return toCmdErr(invalidArgsError("the source and the destination should not be the same object"))
+		}
+		tasks = append(tasks, newCopyTask(objectDetail.ObjectInfo, dstBucket, dstName))
+	}
+
+	operation, operated := "copy", "copied"
+	if isMove {
+		operation, operated = "move", "moved"
+	}
+	if ctx.Bool(dryRunFlag) {
+		var totalSize int64
+		for _, task := range tasks {
+			totalSize += task.resultItem.Size
+			fmt.Printf("%s: %s to %s (dry run)\n", operation, task.resultItem.Name, task.resultItem.Path)
+		}
+		fmt.Printf("%d objects (%s) would be %s\n", len(tasks), getConvertSize(totalSize), operated)
+		return nil
+	}
+	if len(tasks) > confirmThreshold {
+		confirmed, err := confirmOperation(ctx, fmt.Sprintf("%s %d objects to %s%s/%s", operation, len(tasks), urlPrefix, dstBucket, dstName))
+		if err != nil {
+			return toCmdErr(err)
+		}
+		if !confirmed {
+			fmt.Printf("the %s is canceled\n", operation)
+			return nil
+		}
+	}
+
+	partSize := ctx.Uint64(partSizeFlag)
+	waitSeal := isMove || !ctx.Bool(bypassSealFlag)
+	results := make([]*transferResult, 0, len(tasks))
+	for _, task := range tasks {
+		results = append(results, task.resultItem)
+		runCopyTask(c, gnfdClient, task, partSize, waitSeal, isMove)
+	}
+
+	printTransferSummary(results)
+	var failedNum int
+	for _, result := range results {
+		if result.Status == transferStatusFailed {
+			failedNum++
+		}
+	}
+	if failedNum > 0 {
+		return fmt.Errorf("%d of %d objects failed to copy", failedNum, len(results))
+	}
+	return nil
+}
+
+func newCopyTask(srcInfo *storageTypes.ObjectInfo, dstBucket, dstObject string) *copyTask {
+	return &copyTask{
+		srcInfo:   srcInfo,
+		dstBucket: dstBucket,
+		dstObject: dstObject,
+		resultItem: &transferResult{
+			Name: srcInfo.BucketName + "/" + srcInfo.ObjectName,
+			Path: dstBucket + "/" + dstObject,
+			Size: int64(srcInfo.PayloadSize),
+		},
+	}
+}
+
+// runCopyTask copy the object and record the result, the source object is deleted after the destination is sealed if isMove is true
+func runCopyTask(c context.Context, gnfdClient client.IClient, task *copyTask, partSize uint64, waitSeal, isMove bool) {
+	result := task.resultItem
+	srcInfo := task.srcInfo
+	if srcInfo.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+		result.Status, result.Comment = transferStatusSkipped, "source object not sealed"
+		return
+	}
+	if _, err := gnfdClient.HeadObject(c, task.dstBucket, task.dstObject); err == nil {
+		result.Status, result.Comment = transferStatusSkipped, "destination object already exists"
+		return
+	}
+
+	if err := copyObjectPayload(c, gnfdClient, srcInfo, task.dstBucket, task.dstObject, partSize); err != nil {
+		result.fail(err)
+		fmt.Printf("\rfailed to copy %s to %s: %s\n", result.Name, result.Path, err.Error())
+		return
+	}
+
+	if waitSeal {
+		if err := waitObjectSealed(c, gnfdClient, task.dstBucket, task.dstObject, time.Hour); err != nil {
+			result.fail(err)
+			fmt.Printf("\rfailed to copy %s to %s: %s\n", result.Name, result.Path, err.Error())
+			return
+		}
+	}
+
+	if isMove {
+		txnHash, err := gnfdClient.DeleteObject(c, srcInfo.BucketName, srcInfo.ObjectName, sdktypes.DeleteObjectOption{TxOpts: &TxnOptionWithSyncMode})
+		if err == nil {
+			err = waitTxnStatus(gnfdClient, c, txnHash, "DeleteObject")
+		}
+		if err != nil {
+			result.fail(fmt.Errorf("copied but failed to delete the source object: %v", err))
+			fmt.Printf("\rfailed to delete the source object %s: %s\n", result.Name, err.Error())
+			return
+		}
+		fmt.Printf("\rmove %s to %s\n", result.Name, result.Path)
+	} else {
+		fmt.Printf("\rcopy %s to %s\n", result.Name, result.Path)
+	}
+	result.Status = transferStatusSuccess
+}
+
+// copyObjectPayload create the destination object with the meta of the source object and upload the payload
+// streamed from the source object. The source payload is read twice, the first time is for computing the integrity hash
+// when creating the object and the second time is for uploading, so there is no need to keep a local copy.
+func copyObjectPayload(c context.Context, gnfdClient client.IClient, srcInfo *storageTypes.ObjectInfo,
+	dstBucket, dstObject string, partSize uint64,
+) error {
+	opts := sdktypes.CreateObjectOptions{
+		ContentType: srcInfo.ContentType,
+		Visibility:  srcInfo.Visibility,
+	}
+	if srcInfo.Tags != nil && len(srcInfo.Tags.Tags) > 0 {
+		opts.Tags = srcInfo.Tags
+	}
+
+	if strings.HasSuffix(srcInfo.ObjectName, "/") {
+		txnHash, err := gnfdClient.CreateFolder(c, dstBucket, dstObject, opts)
+		if err != nil {
+			return err
+		}
+		return waitTxnStatus(gnfdClient, c, txnHash, "createFolder")
+	}
+
+	body, _, err := gnfdClient.GetObject(c, srcInfo.BucketName, srcInfo.ObjectName, sdktypes.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	txnHash, err := gnfdClient.CreateObject(c, dstBucket, dstObject, body, opts)
+	body.Close()
+	if err != nil {
+		return err
+	}
+	if err = waitTxnStatus(gnfdClient, c, txnHash, "createObject"); err != nil {
+		return err
+	}
+
+	objectSize := int64(srcInfo.PayloadSize)
+	if objectSize == 0 {
+		return nil
+	}
+
+	// the created object would block the later copies to the same name if the payload is not uploaded, so it is canceled
+	if err = putCopiedPayload(c, gnfdClient, srcInfo, dstBucket, dstObject, partSize); err != nil {
+		txnHash, cancelErr := gnfdClient.CancelCreateObject(c, dstBucket, dstObject, sdktypes.CancelCreateOption{TxOpts: &TxnOptionWithSyncMode})
+		if cancelErr == nil {
+			cancelErr = waitTxnStatus(gnfdClient, c, txnHash, "CancelCreateObject")
+		}
+		if cancelErr != nil {
+			return fmt.Errorf("%w, and failed to cancel the created object %s: %v", err, dstObject, cancelErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// putCopiedPayload upload the payload of the source object to the created destination object
+func putCopiedPayload(c context.Context, gnfdClient client.IClient, srcInfo *storageTypes.ObjectInfo, dstBucket, dstObject string,
+	partSize uint64,
+) error {
+	objectSize := int64(srcInfo.PayloadSize)
+	body, _, err := gnfdClient.GetObject(c, srcInfo.BucketName, srcInfo.ObjectName, sdktypes.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	opt := sdktypes.PutObjectOptions{
+		ContentType: srcInfo.ContentType,
+		PartSize:    partSize,
+		// if the object is more than 2G , it needs to force use resume uploading
+		DisableResumable: objectSize <= maxPutWithoutResumeSize,
+	}
+	progressReader := &ProgressReader{
+		Reader:      body,
+		Name:        dstObject,
+		Bucket:      dstBucket,
+		Total:       objectSize,
+		StartTime:   time.Now(),
+		LastPrinted: time.Now(),
+	}
+	return gnfdClient.PutObject(c, dstBucket, dstObject, objectSize, progressReader, opt)
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
+	"github.com/google/uuid"
 	"github.com/urfave/cli/v2"
 	"github.com/zkMeLabs/mechain-go-sdk/client"
 	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
@@ -22,7 +26,12 @@ Send a deleteBucket txn to mechain chain, the bucket must be empty before deleti
 
 Examples:
 # Delete an existed bucket called mechain-bucket
-$ mechain-cmd bucket rm mechain://mechain-bucket/mechain-object`,
+$ mechain-cmd bucket rm mechain://mechain-bucket/mechain-object
+# Show the bucket to be deleted and the objects it contains without deleting it
+$ mechain-cmd bucket rm --dryRun mechain://mechain-bucket`,
+		Flags: []cli.Flag{
+			dryRunCliFlag(),
+		},
 	}
 }
 
@@ -38,14 +47,22 @@ Send a deleteObject txn to mechain chain
 
 Examples:
 # Delete an existed object called mechain-object
-$ mechain-cmd object rm mechain://mechain-bucket/mechain-object`,
-		Flags: []cli.Flag{
+$ mechain-cmd object rm mechain://mechain-bucket/mechain-object
+# Delete the tmp objects under the prefix in a recursive way
+$ mechain-cmd object rm --recursive --include "*.tmp" mechain://mechain-bucket/prefix/
+# Show the objects to be deleted under the prefix without deleting them
+$ mechain-cmd object rm --recursive --dryRun mechain://mechain-bucket/prefix/
+# Delete the objects under the prefix without the confirmation
+$ mechain-cmd object rm --recursive --yes mechain://mechain-bucket/prefix/`,
+		Flags: append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:  recursiveFlag,
 				Value: false,
 				Usage: "performed on all files or objects under the specified directory or prefix in a recursive way",
 			},
-		},
+			dryRunCliFlag(),
+			yesCliFlag(),
+		}, filterFlags()...),
 	}
 }
 
@@ -61,14 +78,19 @@ Send a deleteGroup txn to mechain chain
 
 Examples:
 # Delete an existed group
-$ mechain-cmd group rm group-name`,
+$ mechain-cmd group rm group-name
+# Show the group to be deleted without deleting it
+$ mechain-cmd group rm --dryRun group-name`,
+		Flags: []cli.Flag{
+			dryRunCliFlag(),
+		},
 	}
 }
 
 // deleteBucket send the deleteBucket msg to mechain
 func deleteBucket(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number more than one"))
+		return toCmdErr(invalidArgsError("args number more than one"))
 	}
 	bucketName, err := getBucketNameByUrl(ctx)
 	if err != nil {
@@ -83,15 +105,25 @@ func deleteBucket(ctx *cli.Context) error {
 	c, cancelDelBucket := context.WithCancel(globalContext)
 	defer cancelDelBucket()
 
-	_, err = client.HeadBucket(c, bucketName)
-	if err != nil {
-		fmt.Printf("bucket %s not exist or already deleted\n", bucketName)
+	if _, err = client.HeadBucket(c, bucketName); err != nil {
+		return toCmdErr(ErrBucketNotExist)
+	}
+
+	if ctx.Bool(dryRunFlag) {
+		objects, err := listObjectsToDelete(client, c, bucketName, "", nil)
+		if err != nil {
+			return toCmdErr(err)
+		}
+		fmt.Printf("delete_bucket: %s (dry run)\n", bucketName)
+		if len(objects) > 0 {
+			fmt.Printf("the bucket contains %s, it should be empty before deleting\n", summarizeObjects(objects))
+		}
+		return nil
 	}
 
 	txnHash, err := client.DeleteBucket(c, bucketName, sdktypes.DeleteBucketOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
-		fmt.Println("delete bucket error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	err = waitTxnStatus(client, c, txnHash, "DeleteBucket")
@@ -106,7 +138,7 @@ func deleteBucket(ctx *cli.Context) error {
 // deleteObject send the deleteBucket msg to mechain
 func deleteObject(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number more than one"))
+		return toCmdErr(invalidArgsError("args number more than one"))
 	}
 	var (
 		deleteAll              bool
@@ -141,81 +173,183 @@ func deleteObject(ctx *cli.Context) error {
 	c, cancelDelObject := context.WithCancel(globalContext)
 	defer cancelDelObject()
 	if supportRecursive {
+		filter, err := newObjectFilter(ctx, "")
+		if err != nil {
+			return toCmdErr(err)
+		}
+
 		if !deleteAll {
 			// if it is a folder and set the --recursive flag , list all the objects and delete them one by one
 			prefixName = objectName
 			if !strings.HasSuffix(prefixName, "/") {
 				prefixName = objectName + "/"
 			}
-			err = deleteObjectByPage(client, c, bucketName, prefixName)
-		} else {
-			// list all the objects in the bucket and delete them
-			err = deleteObjectByPage(client, c, bucketName, prefixName)
 		}
+		// list the objects under the prefix or all the objects in the bucket and delete them
+		objects, err := listObjectsToDelete(client, c, bucketName, prefixName, filter)
+		if err != nil {
+			return toCmdErr(err)
+		}
+		if len(objects) == 0 {
+			fmt.Println("no object to delete")
+			return nil
+		}
+
+		if ctx.Bool(dryRunFlag) {
+			printObjectsToDelete(objects)
+			return nil
+		}
+
+		confirmed, err := confirmOperation(ctx, fmt.Sprintf("delete %s under %s%s/%s", summarizeObjects(objects), urlPrefix, bucketName, prefixName))
 		if err != nil {
 			return toCmdErr(err)
 		}
+		if !confirmed {
+			fmt.Println("the deletion is canceled")
+			return nil
+		}
 
+		return deleteObjectsAsTask(ctx, client, bucketName, prefixName, objects)
 	} else {
-		deleteObjectAndWaitTxn(client, c, bucketName, objectName)
+		if ctx.Bool(dryRunFlag) {
+			objectDetail, err := client.HeadObject(c, bucketName, objectName)
+			if err != nil {
+				return toCmdErr(ErrObjectNotExist)
+			}
+			printObjectsToDelete([]*storageTypes.ObjectInfo{objectDetail.ObjectInfo})
+			return nil
+		}
+		if err = deleteObjectAndWaitTxn(client, c, bucketName, objectName); err != nil {
+			return toCmdErr(err)
+		}
 	}
 
 	return nil
 }
 
-func deleteObjectByPage(cli client.IClient, c context.Context, bucketName, prefixName string) error {
-	var (
-		listResult        sdktypes.ListObjectsResult
-		continuationToken string
-		err               error
-	)
-
-	for {
-		listResult, err = cli.ListObjects(c, bucketName, sdktypes.ListObjectsOptions{
-			ShowRemovedObject: false,
-			MaxKeys:           defaultMaxKey,
-			ContinuationToken: continuationToken,
-			Prefix:            prefixName,
-		})
-		if err != nil {
-			return toCmdErr(err)
-		}
-
-		// TODO use one txn to broadcast multi delete object messages
+// listObjectsToDelete list the objects under the prefix which are selected by the filter
+func listObjectsToDelete(cli client.IClient, c context.Context, bucketName, prefixName string, filter *objectFilter) ([]*storageTypes.ObjectInfo, error) {
+	objects := make([]*storageTypes.ObjectInfo, 0)
+	err := walkObjectsByPage(cli, c, bucketName, prefixName, true, func(listResult sdktypes.ListObjectsResult) error {
 		for _, object := range listResult.Objects {
-			// no need to return err if some objects delete failed
-			deleteObjectAndWaitTxn(cli, c, bucketName, object.ObjectInfo.ObjectName)
+			objectName := object.ObjectInfo.ObjectName
+			if object.Removed || !filter.match(relativeObjectName(prefixName, objectName), strings.HasSuffix(objectName, "/")) {
+				continue
+			}
+			objects = append(objects, object.ObjectInfo)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
 
-		if !listResult.IsTruncated {
-			break
+// deleteObjectsAsTask delete the objects by a delete task, the deletion runs as a task so that it can be retried
+// after being interrupted
+func deleteObjectsAsTask(ctx *cli.Context, gnfdClient client.IClient, bucketName, prefixName string, objects []*storageTypes.ObjectInfo) error {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	taskState := &TaskState{
+		Lock:        new(sync.Mutex),
+		ObjectState: make(map[int]*UploadTaskObject),
+		TaskID:      uuid.New().String(),
+		Kind:        TaskKindDelete,
+		Prefix:      prefixName,
+		BucketName:  bucketName,
+		Status:      TaskStatusCreate,
+		CreateTime:  time.Now().Unix(),
+	}
+	for index, object := range objects {
+		taskState.ObjectState[index] = &UploadTaskObject{
+			BucketName:         bucketName,
+			ObjectName:         object.ObjectName,
+			UploadSingleFolder: strings.HasSuffix(object.ObjectName, "/"),
+			ObjectSize:         int64(object.PayloadSize),
+			Status:             TaskObjectStatusWaitForDelete,
 		}
+	}
+	printTaskSubmitted(TaskKindDelete, taskState.TaskID)
+	if err = saveTaskState(homeDir, taskState); err != nil {
+		return err
+	}
+	return deleteObjectsByTask(ctx, homeDir, gnfdClient, taskState)
+}
 
-		continuationToken = listResult.NextContinuationToken
+// printObjectsToDelete print the objects which would be deleted and the summary of them
+func printObjectsToDelete(objects []*storageTypes.ObjectInfo) {
+	for _, object := range objects {
+		fmt.Printf("delete: %15d %s (dry run)\n", object.PayloadSize, object.ObjectName)
 	}
-	return nil
+	fmt.Printf("%s would be deleted\n", summarizeObjects(objects))
+}
+
+// summarizeObjects return the number and the total payload size of the objects
+func summarizeObjects(objects []*storageTypes.ObjectInfo) string {
+	var totalSize int64
+	for _, object := range objects {
+		totalSize += int64(object.PayloadSize)
+	}
+	return fmt.Sprintf("%d objects (%s)", len(objects), getConvertSize(totalSize))
 }
 
-func deleteObjectAndWaitTxn(cli client.IClient, c context.Context, bucketName, objectName string) {
+// deleteObjectsByTask delete the unfinished objects of the delete task
+func deleteObjectsByTask(ctx *cli.Context, homeDir string, gnfdClient client.IClient, taskState *TaskState) error {
+	c, cancelDelObject := context.WithCancel(globalContext)
+	defer cancelDelObject()
+
+	// TODO use one txn to broadcast multi delete object messages
+	// the objects are deleted one by one, so that the txns of the account will not conflict
+	return runObjectTask(ctx, homeDir, taskState, 1, func(object *UploadTaskObject) (string, error) {
+		// the object may have been deleted before the task was interrupted
+		objectDetail, err := gnfdClient.HeadObject(c, object.BucketName, object.ObjectName)
+		if isObjectNotExistErr(err) {
+			return TaskObjectStatusDeleted, nil
+		}
+		// the object which is not sealed can't be deleted, it is canceled instead
+		if err == nil && objectDetail.ObjectInfo.GetObjectStatus() == storageTypes.OBJECT_STATUS_CREATED {
+			txnHash, err := gnfdClient.CancelCreateObject(c, object.BucketName, object.ObjectName, sdktypes.CancelCreateOption{TxOpts: &TxnOptionWithSyncMode})
+			if err != nil {
+				return "", err
+			}
+			if err = waitTxnStatus(gnfdClient, c, txnHash, "CancelCreateObject"); err != nil {
+				return "", err
+			}
+			return TaskObjectStatusDeleted, nil
+		}
+		txnHash, err := gnfdClient.DeleteObject(c, object.BucketName, object.ObjectName, sdktypes.DeleteObjectOption{TxOpts: &TxnOptionWithSyncMode})
+		if err != nil {
+			return "", err
+		}
+		if err = waitTxnStatus(gnfdClient, c, txnHash, "DeleteObject"); err != nil {
+			return "", err
+		}
+		return TaskObjectStatusDeleted, nil
+	})
+}
+
+func deleteObjectAndWaitTxn(cli client.IClient, c context.Context, bucketName, objectName string) error {
 	txnHash, err := cli.DeleteObject(c, bucketName, objectName, sdktypes.DeleteObjectOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
-		fmt.Printf("failed to delete object %s err:%v\n", objectName, err)
-		return
+		return fmt.Errorf("failed to delete object %s: %w", objectName, err)
 	}
 
 	err = waitTxnStatus(cli, c, txnHash, "DeleteObject")
 	if err != nil {
-		fmt.Printf("failed to query the txn of deleting object %s, err:%v\n", objectName, err)
-		return
+		return fmt.Errorf("failed to query the txn of deleting object %s: %w", objectName, err)
 	}
 
 	fmt.Printf("delete: %s\n", objectName)
+	return nil
 }
 
 // deleteGroup send the deleteGroup msg to mechain
 func deleteGroup(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number more than one"))
+		return toCmdErr(invalidArgsError("args number more than one"))
 	}
 	groupName, err := getGroupNameByUrl(ctx)
 	if err != nil {
@@ -229,6 +363,20 @@ func deleteGroup(ctx *cli.Context) error {
 
 	c, cancelDelGroup := context.WithCancel(globalContext)
 	defer cancelDelGroup()
+
+	if ctx.Bool(dryRunFlag) {
+		groupOwner, err := getGroupOwner(ctx)
+		if err != nil {
+			return toCmdErr(err)
+		}
+		groupInfo, err := client.HeadGroup(c, groupName, groupOwner)
+		if err != nil {
+			return toCmdErr(ErrGroupNotExist)
+		}
+		fmt.Printf("delete_group: %s, id: %s, owner: %s (dry run)\n", groupInfo.GroupName, groupInfo.Id.String(), groupInfo.Owner)
+		return nil
+	}
+
 	txnHash, err := client.DeleteGroup(c, groupName, sdktypes.DeleteGroupOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
 		return toCmdErr(err)
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/math"
+	"github.com/urfave/cli/v2"
+
+	"github.com/zkMeLabs/mechain-go-sdk/client"
+	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
+)
+
+// secondsPerMonth is the seconds of the month by which the monthly storage cost is estimated
+const secondsPerMonth = 30 * 24 * 3600
+
+// cmdDuObjects return the command to summarize the space used by the objects of the bucket
+func cmdDuObjects() *cli.Command {
+	return &cli.Command{
+		Name:      "du",
+		Action:    duObjects,
+		Usage:     "summarize the object count and size of the bucket by prefix",
+		ArgsUsage: "BUCKET-URL",
+		Description: `
+Summarize the number and the payload size of the objects under the bucket or the prefix. The objects are grouped
+by the folders of the first --depth levels under the prefix, the objects which are not in such a folder are counted
+to the prefix itself. The grand total is shown at the end.
+
+If --cost is set, the monthly storage cost of every group is estimated by the storage price of the primary SP of the bucket,
+the estimate doesn't include the secondary SPs and the minimum charged size of the objects.
+
+Examples:
+$ mechain-cmd object du mechain://mechain-bucket
+# show the usage of the folders of two levels under the prefix with the estimated monthly cost
+$ mechain-cmd object du --depth 2 --human-readable --cost mechain://mechain-bucket/logs/`,
+		Flags: append([]cli.Flag{
+			&cli.IntFlag{
+				Name:  depthFlag,
+				Value: 1,
+				Usage: "the levels of the folders under the prefix by which the objects are grouped",
+			},
+			&cli.BoolFlag{
+				Name:    humanReadableFlag,
+				Aliases: []string{"human-readable"},
+				Value:   false,
+				Usage:   "show the sizes in K, M and G",
+			},
+			&cli.BoolFlag{
+				Name:  costFlag,
+				Value: false,
+				Usage: "estimate the monthly storage cost by the storage price of the primary SP of the bucket",
+			},
+		}, filterFlags()...),
+	}
+}
+
+func duObjects(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return toCmdErr(invalidArgsError("args number should be one"))
+	}
+
+	bucketName, prefixName, err := ParseBucketAndPrefix(ctx.Args().Get(0))
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	depth := ctx.Int(depthFlag)
+	if depth < 1 {
+		return toCmdErr(invalidArgsError("the depth should be at least 1"))
+	}
+
+	filter, err := newObjectFilter(ctx, "")
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	client, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
+	if err != nil {
+		return toCmdErr(err)
+	}
+	c, cancelDu := context.WithCancel(globalContext)
+	defer cancelDu()
+
+	_, err = client.HeadBucket(c, bucketName)
+	if err != nil {
+		return toCmdErr(ErrBucketNotExist)
+	}
+
+	usages := make(map[string]*objectUsage)
+	err = walkObjectsByPage(client, c, bucketName, prefixName, true, func(listResult sdktypes.ListObjectsResult) error {
+		listResult = filterListResult(listResult, prefixName, filter)
+		for _, object := range listResult.Objects {
+			objectName := object.ObjectInfo.ObjectName
+			// the folders are empty objects which are not counted
+			if strings.HasSuffix(objectName, "/") {
+				continue
+			}
+			groupName := usageGroupName(prefixName, objectName, depth)
+			usage, ok := usages[groupName]
+			if !ok {
+				usage = &objectUsage{Prefix: groupName}
+				usages[groupName] = usage
+			}
+			usage.Objects++
+			usage.Size += int64(object.ObjectInfo.PayloadSize)
+		}
+		return nil
+	})
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	output := objectUsageOutput{
+		Prefixes: make([]objectUsage, 0, len(usages)),
+		Total:    objectUsage{Prefix: prefixName},
+	}
+	for _, usage := range usages {
+		output.Prefixes = append(output.Prefixes, *usage)
+		output.Total.Objects += usage.Objects
+		output.Total.Size += usage.Size
+	}
+	sort.Slice(output.Prefixes, func(i, j int) bool {
+		return output.Prefixes[i].Prefix < output.Prefixes[j].Prefix
+	})
+
+	if ctx.Bool(costFlag) {
+		storePrice, err := getPrimarySPStorePrice(client, c, bucketName)
+		if err != nil {
+			return toCmdErr(err)
+		}
+		output.StorePrice = storePrice.String()
+		for i := range output.Prefixes {
+			output.Prefixes[i].MonthlyCost = estimateMonthlyCost(storePrice, output.Prefixes[i].Size)
+		}
+		output.Total.MonthlyCost = estimateMonthlyCost(storePrice, output.Total.Size)
+	}
+
+	if isStructuredOutput() {
+		return printOutput(output)
+	}
+	printObjectUsage(bucketName, output, ctx.Bool(humanReadableFlag), ctx.Bool(costFlag))
+	return nil
+}
+
+// usageGroupName return the prefix by which the object is counted, which is the folder part of the prefix with
+// the folders of the first depth levels of the relative object name
+func usageGroupName(prefixName, objectName string, depth int) string {
+	relativeName := relativeObjectName(prefixName, objectName)
+	folders := strings.Split(relativeName, "/")
+	// the last part is the name of the object
+	folders = folders[:len(folders)-1]
+	if len(folders) > depth {
+		folders = folders[:depth]
+	}
+	groupName := strings.TrimSuffix(objectName, relativeName)
+	for _, folder := range folders {
+		groupName += folder + "/"
+	}
+	return groupName
+}
+
+// getPrimarySPStorePrice query the storage price of the primary SP of the bucket
+func getPrimarySPStorePrice(cli client.IClient, c context.Context, bucketName string) (math.LegacyDec, error) {
+	bucketInfo, err := cli.HeadBucket(c, bucketName)
+	if err != nil {
+		return math.LegacyDec{}, ErrBucketNotExist
+	}
+	gvgf, err := cli.QueryVirtualGroupFamily(c, bucketInfo.GlobalVirtualGroupFamilyId)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("no such virtual group family %d: %v", bucketInfo.GlobalVirtualGroupFamilyId, err)
+	}
+
+	spList, err := cli.ListStorageProviders(c, false)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("fail to get SP info: %v", err)
+	}
+	for _, info := range spList {
+		if info.Id != gvgf.PrimarySpId {
+			continue
+		}
+		price, err := cli.GetStoragePrice(c, info.OperatorAddress)
+		if err != nil {
+			return math.LegacyDec{}, err
+		}
+		return price.StorePrice, nil
+	}
+	return math.LegacyDec{}, newCmdError(ErrNotFound, fmt.Errorf("the primary SP %d of the bucket is not found", gvgf.PrimarySpId))
+}
+
+// estimateMonthlyCost return the storage cost of the size for a month in wei
+func estimateMonthlyCost(storePrice math.LegacyDec, size int64) string {
+	return storePrice.MulInt64(size).MulInt64(secondsPerMonth).TruncateInt().String()
+}
+
+func printObjectUsage(bucketName string, output objectUsageOutput, humanReadable, withCost bool) {
+	formatSize := func(size int64) string {
+		if humanReadable {
+			return getConvertSize(size)
+		}
+		return fmt.Sprintf("%d", size)
+	}
+
+	sizeWidth, costWidth := len("size"), len("monthly cost(wei)")
+	for _, usage := range append(output.Prefixes, output.Total) {
+		sizeWidth = max(sizeWidth, len(formatSize(usage.Size)))
+		costWidth = max(costWidth, len(usage.MonthlyCost))
+	}
+
+	printRow := func(objects, size, cost, prefix string) {
+		if withCost {
+			fmt.Printf("%10s  %*s  %*s  %s\n", objects, sizeWidth, size, costWidth, cost, prefix)
+		} else {
+			fmt.Printf("%10s  %*s  %s\n", objects, sizeWidth, size, prefix)
+		}
+	}
+
+	printRow("objects", "size", "monthly cost(wei)", "prefix")
+	for _, usage := range output.Prefixes {
+		printRow(fmt.Sprintf("%d", usage.Objects), formatSize(usage.Size), usage.MonthlyCost, urlPrefix+bucketName+"/"+usage.Prefix)
+	}
+	printRow(fmt.Sprintf("%d", output.Total.Objects), formatSize(output.Total.Size), output.Total.MonthlyCost, "total")
+	if withCost {
+		fmt.Println("storage price of the primary SP:", output.StorePrice, "wei/byte/second")
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func Test_usageGroupName(t *testing.T) {
+	tests := []struct {
+		prefixName string
+		objectName string
+		depth      int
+		want       string
+	}{
+		{prefixName: "", objectName: "a.txt", depth: 1, want: ""},
+		{prefixName: "", objectName: "logs/2024/a.log", depth: 1, want: "logs/"},
+		{prefixName: "", objectName: "logs/2024/a.log", depth: 2, want: "logs/2024/"},
+		{prefixName: "", objectName: "logs/2024/a.log", depth: 3, want: "logs/2024/"},
+		{prefixName: "logs/", objectName: "logs/2024/01/a.log", depth: 1, want: "logs/2024/"},
+		{prefixName: "logs/", objectName: "logs/a.log", depth: 1, want: "logs/"},
+		{prefixName: "logs/20", objectName: "logs/2024/a.log", depth: 1, want: "logs/2024/"},
+		{prefixName: "lo", objectName: "logs/2024/a.log", depth: 1, want: "logs/"},
+	}
+	for _, tt := range tests {
+		if got := usageGroupName(tt.prefixName, tt.objectName, tt.depth); got != tt.want {
+			t.Errorf("usageGroupName(%q, %q, %d) = %q, want %q", tt.prefixName, tt.objectName, tt.depth, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	mechaindTypes "github.com/evmos/evmos/v12/types"
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
+	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
+)
+
+// cmdFindObjects return the command to find the objects by the metadata and run an action on them
+func cmdFindObjects() *cli.Command {
+	return &cli.Command{
+		Name:      "find",
+		Action:    findObjects,
+		Usage:     "find the objects by the name, the size, the create time, the status, the visibility, the content type and the tags",
+		ArgsUsage: "BUCKET-URL",
+		Description: `
+Find the objects under the bucket or the prefix in a recursive way, the objects should match all of the set predicates.
+The names of the matched objects are printed by default, --long prints the full records of them.
+
+The sizes are like 512K, 20M or 1G. The create times are like 2024-03-01, "2024-03-01 10:00:00" in the time zone of --tz,
+2024-03-01T10:00:00Z, the unix seconds, or the duration before now such as 36h or 7d. --createdAfter is inclusive
+and --createdBefore is exclusive. The --tag flag is "key=value" or "key" to match any value, and it can be repeated.
+
+The matched objects can be deleted by --delete, tagged by --setTags or updated by --setVisibility. The action needs
+to be confirmed unless --yes is set, and --dryRun shows the objects to be changed without changing them. Note that
+--setTags replaces all the existing tags of the objects, and --delete cancels the objects which are not sealed.
+
+Examples:
+# find the objects under logs/ larger than 1G created before March
+$ mechain-cmd object find --minSize 1G --createdBefore 2024-03-01 mechain://mechain-bucket/logs/
+# show the full records of the objects which are not sealed
+$ mechain-cmd object find --status created -l mechain://mechain-bucket
+# delete the tmp objects created more than 7 days ago
+$ mechain-cmd object find --name '\.tmp$' --createdBefore 7d --delete mechain://mechain-bucket
+# make the objects tagged env=test private
+$ mechain-cmd object find --tag env=test --setVisibility private mechain://mechain-bucket`,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  nameRegexFlag,
+				Usage: "the regular expression which matches the object name",
+			},
+			&cli.StringFlag{
+				Name:    minSizeFlag,
+				Aliases: []string{"min-size"},
+				Usage:   "the minimum payload size of the objects, such as 512K or 1G",
+			},
+			&cli.StringFlag{
+				Name:    maxSizeFlag,
+				Aliases: []string{"max-size"},
+				Usage:   "the maximum payload size of the objects, such as 512K or 1G",
+			},
+			&cli.StringFlag{
+				Name:    createdAfterFlag,
+				Aliases: []string{"created-after"},
+				Usage:   "find the objects created at or after the time, such as 2024-03-01 or 7d",
+			},
+			&cli.StringFlag{
+				Name:    createdBeforeFlag,
+				Aliases: []string{"created-before"},
+				Usage:   "find the objects created before the time, such as 2024-03-01 or 7d",
+			},
+			&cli.GenericFlag{
+				Name: objectStatusFlag,
+				Value: &CmdEnumValue{
+					Enum: []string{objectStatusCreated, objectStatusSealed, objectStatusDiscontinued},
+				},
+				Usage: "the status of the objects, created means the objects are not sealed",
+			},
+			&cli.GenericFlag{
+				Name: visibilityFlag,
+				Value: &CmdEnumValue{
+					Enum: []string{publicReadType, privateType, inheritType},
+				},
+				Usage: "the visibility of the objects",
+			},
+			&cli.StringFlag{
+				Name:    contentTypeFlag,
+				Aliases: []string{"content-type"},
+				Usage:   "the content type of the objects, the glob such as image/* is supported",
+			},
+			&cli.StringSliceFlag{
+				Name:  tagFilterFlag,
+				Usage: "the tag of the objects in key=value format, or the key only to match any value. The flag can be repeated",
+			},
+			&cli.BoolFlag{
+				Name:    longFlag,
+				Aliases: []string{"l"},
+				Value:   false,
+				Usage:   "show the full records of the objects instead of the names",
+			},
+			&cli.BoolFlag{
+				Name:    humanReadableFlag,
+				Aliases: []string{"human-readable"},
+				Value:   false,
+				Usage:   "show the sizes in K, M and G",
+			},
+			&cli.BoolFlag{
+				Name:  deleteFlag,
+				Value: false,
+				Usage: "delete the matched objects",
+			},
+			&cli.StringFlag{
+				Name:  setTagsFlag,
+				Usage: "set the tags of the matched objects in json array format. E.g. [{\"key\":\"key1\",\"value\":\"value1\"}]",
+			},
+			&cli.GenericFlag{
+				Name: setVisibilityFlag,
+				Value: &CmdEnumValue{
+					Enum: []string{publicReadType, privateType, inheritType},
+				},
+				Usage: "update the visibility of the matched objects",
+			},
+			dryRunCliFlag(),
+			yesCliFlag(),
+		}, filterFlags()...),
+	}
+}
+
+// objectQuery is the metadata predicates of "object find", the predicates which are not set match all the objects
+type objectQuery struct {
+	nameRegex *regexp.Regexp
+	minSize   int64
+	// maxSize is negative if there is no upper bound of the size
+	maxSize       int64
+	createdAfter  time.Time
+	createdBefore time.Time
+	// status is the name of the object status on chain, such as OBJECT_STATUS_SEALED
+	status      string
+	visibility  storageTypes.VisibilityType
+	contentType string
+	tags        []tagPredicate
+}
+
+// tagPredicate matches the tag of the key, and the value if anyValue is false
+type tagPredicate struct {
+	key      string
+	value    string
+	anyValue bool
+}
+
+// newObjectQuery build the query from the flags of "object find", the relative times are before now
+func newObjectQuery(ctx *cli.Context, now time.Time) (*objectQuery, error) {
+	query := &objectQuery{maxSize: -1}
+	var err error
+	if name := ctx.String(nameRegexFlag); name != "" {
+		if query.nameRegex, err = regexp.Compile(name); err != nil {
+			return nil, invalidArgsError("invalid name regular expression %s: %v", name, err)
+		}
+	}
+	if minSize := ctx.String(minSizeFlag); minSize != "" {
+		if query.minSize, err = parseSize(minSize); err != nil {
+			return nil, invalidArgsError("%v", err)
+		}
+	}
+	if maxSize := ctx.String(maxSizeFlag); maxSize != "" {
+		if query.maxSize, err = parseSize(maxSize); err != nil {
+			return nil, invalidArgsError("%v", err)
+		}
+	}
+	if createdAfter := ctx.String(createdAfterFlag); createdAfter != "" {
+		if query.createdAfter, err = parseTimeBound(createdAfter, now); err != nil {
+			return nil, err
+		}
+	}
+	if createdBefore := ctx.String(createdBeforeFlag); createdBefore != "" {
+		if query.createdBefore, err = parseTimeBound(createdBefore, now); err != nil {
+			return nil, err
+		}
+	}
+	if status := fmt.Sprintf("%s", ctx.Generic(objectStatusFlag)); status != "" {
+		query.status = "OBJECT_STATUS_" + strings.ToUpper(status)
+	}
+	if visibility := fmt.Sprintf("%s", ctx.Generic(visibilityFlag)); visibility != "" {
+		if query.visibility, err = getVisibilityType(visibility); err != nil {
+			return nil, invalidArgsError("%v", err)
+		}
+	}
+	if query.contentType = ctx.String(contentTypeFlag); query.contentType != "" {
+		if _, err = path.Match(query.contentType, ""); err != nil {
+			return nil, invalidArgsError("invalid content type pattern %s: %v", query.contentType, err)
+		}
+	}
+	for _, tag := range ctx.StringSlice(tagFilterFlag) {
+		key, value, found := strings.Cut(tag, "=")
+		if key == "" {
+			return nil, invalidArgsError("invalid tag %s, it should be key=value or key", tag)
+		}
+		query.tags = append(query.tags, tagPredicate{key: key, value: value, anyValue: !found})
+	}
+	return query, nil
+}
+
+// match report whether the object matches all the predicates of the query
+func (q *objectQuery) match(info *storageTypes.ObjectInfo) bool {
+	if q.nameRegex != nil && !q.nameRegex.MatchString(info.ObjectName) {
+		return false
+	}
+	size := int64(info.PayloadSize)
+	if size < q.minSize || q.maxSize >= 0 && size > q.maxSize {
+		return false
+	}
+	if !q.createdAfter.IsZero() && info.CreateAt < q.createdAfter.Unix() {
+		return false
+	}
+	if !q.createdBefore.IsZero() && info.CreateAt >= q.createdBefore.Unix() {
+		return false
+	}
+	if q.status != "" && info.ObjectStatus.String() != q.status {
+		return false
+	}
+	if q.visibility != storageTypes.VISIBILITY_TYPE_UNSPECIFIED && info.Visibility != q.visibility {
+		return false
+	}
+	if q.contentType != "" {
+		if ok, _ := path.Match(q.contentType, info.ContentType); !ok {
+			return false
+		}
+	}
+	for _, predicate := range q.tags {
+		if !predicate.match(info.Tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// match report whether one of the tags matches the predicate
+func (p tagPredicate) match(tags *storageTypes.ResourceTags) bool {
+	if tags == nil {
+		return false
+	}
+	for _, tag := range tags.Tags {
+		if tag.Key == p.key && (p.anyValue || tag.Value == p.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func findObjects(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return toCmdErr(invalidArgsError("args number should be one"))
+	}
+
+	bucketName, prefixName, err := ParseBucketAndPrefix(ctx.Args().Get(0))
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	query, err := newObjectQuery(ctx, time.Now())
+	if err != nil {
+		return toCmdErr(err)
+	}
+	filter, err := newObjectFilter(ctx, "")
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	// only one action can be run on the matched objects
+	var actions []string
+	if ctx.Bool(deleteFlag) {
+		actions = append(actions, "--"+deleteFlag)
+	}
+	for _, flag := range []string{setTagsFlag, setVisibilityFlag} {
+		if ctx.IsSet(flag) {
+			actions = append(actions, "--"+flag)
+		}
+	}
+	if len(actions) > 1 {
+		return toCmdErr(invalidArgsError("only one of the actions can be set, but %s are set", strings.Join(actions, ", ")))
+	}
+	var tags *storageTypes.ResourceTags
+	if ctx.IsSet(setTagsFlag) {
+		tags = &storageTypes.ResourceTags{}
+		if err = json.Unmarshal([]byte(ctx.String(setTagsFlag)), &tags.Tags); err != nil {
+			return toCmdErr(invalidArgsError("invalid tags %s: %v", ctx.String(setTagsFlag), err))
+		}
+	}
+	visibility := storageTypes.VISIBILITY_TYPE_UNSPECIFIED
+	if ctx.IsSet(setVisibilityFlag) {
+		if visibility, err = getVisibilityType(fmt.Sprintf("%s", ctx.Generic(setVisibilityFlag))); err != nil {
+			return toCmdErr(invalidArgsError("%v", err))
+		}
+	}
+
+	client, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
+	if err != nil {
+		return toCmdErr(err)
+	}
+	c, cancelFind := context.WithCancel(globalContext)
+	defer cancelFind()
+
+	_, err = client.HeadBucket(c, bucketName)
+	if err != nil {
+		return toCmdErr(ErrBucketNotExist)
+	}
+
+	var matched []*sdktypes.ObjectMeta
+	err = walkObjectsByPage(client, c, bucketName, prefixName, true, func(listResult sdktypes.ListObjectsResult) error {
+		listResult = filterListResult(listResult, prefixName, filter)
+		for _, object := range listResult.Objects {
+			if !object.Removed && query.match(object.ObjectInfo) {
+				matched = append(matched, object)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	if len(actions) == 0 {
+		return printFoundObjects(matched, ctx.Bool(longFlag), ctx.Bool(humanReadableFlag))
+	}
+
+	if len(matched) == 0 {
+		fmt.Println("no object matched")
+		return nil
+	}
+	objects := make([]*storageTypes.ObjectInfo, 0, len(matched))
+	for _, object := range matched {
+		objects = append(objects, object.ObjectInfo)
+	}
+
+	// the label is the prefix of the lines of the changed objects
+	operation, label := "delete", "delete"
+	if tags != nil {
+		operation, label = "set the tags of", "set-tags"
+	} else if visibility != storageTypes.VISIBILITY_TYPE_UNSPECIFIED {
+		operation, label = "update the visibility of", "update-visibility"
+	}
+	if ctx.Bool(dryRunFlag) {
+		for _, object := range objects {
+			fmt.Printf("%s: %15d %s (dry run)\n", label, object.PayloadSize, object.ObjectName)
+		}
+		fmt.Printf("%s would be changed\n", summarizeObjects(objects))
+		return nil
+	}
+
+	confirmed, err := confirmOperation(ctx, fmt.Sprintf("%s %s under %s%s/%s", operation, summarizeObjects(objects), urlPrefix, bucketName, prefixName))
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if !confirmed {
+		fmt.Println("the operation is canceled")
+		return nil
+	}
+
+	switch {
+	case tags != nil:
+		err = updateFoundObjects(objects, operation, label, func(objectName string) error {
+			grn := mechaindTypes.NewObjectGRN(bucketName, objectName)
+			txnHash, err := client.SetTag(c, grn.String(), *tags, sdktypes.SetTagsOptions{})
+			if err != nil {
+				return err
+			}
+			return waitTxnStatus(client, c, txnHash, "SetTags")
+		})
+	case visibility != storageTypes.VISIBILITY_TYPE_UNSPECIFIED:
+		err = updateFoundObjects(objects, operation, label, func(objectName string) error {
+			txnHash, err := client.UpdateObjectVisibility(c, bucketName, objectName, visibility,
+				sdktypes.UpdateObjectOption{TxOpts: &TxnOptionWithSyncMode})
+			if err != nil {
+				return err
+			}
+			return waitTxnStatus(client, c, txnHash, "UpdateObject")
+		})
+	default:
+		err = deleteObjectsAsTask(ctx, client, bucketName, prefixName, objects)
+	}
+	return toCmdErr(err)
+}
+
+// printFoundObjects print the names or the full records of the matched objects
+func printFoundObjects(objects []*sdktypes.ObjectMeta, long, humanReadable bool) error {
+	if isStructuredOutput() {
+		output := make([]objectOutput, 0, len(objects))
+		for _, object := range objects {
+			output = append(output, toObjectOutput(object))
+		}
+		return printOutput(output)
+	}
+	if !long {
+		for _, object := range objects {
+			fmt.Println(object.ObjectInfo.ObjectName)
+		}
+		return nil
+	}
+	printLongListHeader()
+	printListResult(sdktypes.ListObjectsResult{Objects: objects}, objectListOption{Long: true, HumanReadable: humanReadable})
+	return nil
+}
+
+// updateFoundObjects run the update on the objects one by one, the failed objects are reported and skipped
+func updateFoundObjects(objects []*storageTypes.ObjectInfo, operation, label string, update func(objectName string) error) error {
+	var failed int
+	var lastErr error
+	for _, object := range objects {
+		if err := update(object.ObjectName); err != nil {
+			failed++
+			lastErr = err
+			fmt.Fprintf(os.Stderr, "failed to %s %s: %v\n", operation, object.ObjectName, err)
+			continue
+		}
+		fmt.Printf("%s: %s\n", label, object.ObjectName)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d objects: %w", operation, failed, len(objects), lastErr)
+	}
+	return nil
+}
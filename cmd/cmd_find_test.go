@@ -0,0 +1,68 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
+)
+
+func Test_parseSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{value: "", want: 0},
+		{value: "100", want: 100},
+		{value: "512K", want: 512 << 10},
+		{value: "1.5m", want: 3 << 19},
+		{value: "1GB", want: 1 << 30},
+		{value: "2TiB", want: 2 << 40},
+	}
+	for _, tt := range tests {
+		if got, err := parseSize(tt.value); err != nil || got != tt.want {
+			t.Errorf("parseSize(%q) = %d %v, want %d", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := parseSize("10X"); err == nil {
+		t.Error("parseSize should fail with the invalid unit")
+	}
+}
+
+func Test_objectQuery_match(t *testing.T) {
+	object := &storageTypes.ObjectInfo{
+		ObjectName:   "logs/2024/app.log",
+		PayloadSize:  2 << 30,
+		CreateAt:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		ObjectStatus: storageTypes.OBJECT_STATUS_SEALED,
+		Visibility:   storageTypes.VISIBILITY_TYPE_PRIVATE,
+		ContentType:  "text/plain",
+		Tags:         &storageTypes.ResourceTags{Tags: []storageTypes.ResourceTags_Tag{{Key: "env", Value: "prod"}}},
+	}
+	tests := []struct {
+		name  string
+		query objectQuery
+		want  bool
+	}{
+		{name: "empty", query: objectQuery{maxSize: -1}, want: true},
+		{name: "name", query: objectQuery{maxSize: -1, nameRegex: regexp.MustCompile(`\.log$`)}, want: true},
+		{name: "name mismatch", query: objectQuery{maxSize: -1, nameRegex: regexp.MustCompile(`^tmp/`)}, want: false},
+		{name: "size range", query: objectQuery{minSize: 1 << 30, maxSize: 4 << 30}, want: true},
+		{name: "too small", query: objectQuery{maxSize: 1 << 30}, want: false},
+		{name: "created before", query: objectQuery{maxSize: -1, createdBefore: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, want: true},
+		{name: "created after", query: objectQuery{maxSize: -1, createdAfter: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, want: false},
+		{name: "status", query: objectQuery{maxSize: -1, status: "OBJECT_STATUS_CREATED"}, want: false},
+		{name: "visibility", query: objectQuery{maxSize: -1, visibility: storageTypes.VISIBILITY_TYPE_PRIVATE}, want: true},
+		{name: "content type", query: objectQuery{maxSize: -1, contentType: "text/*"}, want: true},
+		{name: "content type mismatch", query: objectQuery{maxSize: -1, contentType: "image/*"}, want: false},
+		{name: "tag", query: objectQuery{maxSize: -1, tags: []tagPredicate{{key: "env", value: "prod"}}}, want: true},
+		{name: "tag key", query: objectQuery{maxSize: -1, tags: []tagPredicate{{key: "env", anyValue: true}}}, want: true},
+		{name: "tag value mismatch", query: objectQuery{maxSize: -1, tags: []tagPredicate{{key: "env", value: "test"}}}, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.query.match(object); got != tt.want {
+			t.Errorf("%s: match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -518,6 +518,7 @@ func listGroupMember(ctx *cli.Context) error {
 		return toCmdErr(ErrGroupNotExist)
 	}
 
+	output := make([]groupMemberOutput, 0)
 	initStartKey := ""
 	for {
 		memberList, err := client.ListGroupMembers(c, int64(groupInfo.Id.Uint64()),
@@ -526,7 +527,11 @@ func listGroupMember(ctx *cli.Context) error {
 			return toCmdErr(err)
 		}
 
-		printListMemberResult(memberList)
+		if isStructuredOutput() {
+			output = append(output, toGroupMemberOutput(memberList)...)
+		} else {
+			printListMemberResult(memberList)
+		}
 		memberNum := len(memberList.Groups)
 		if memberNum < maxListMemberNum {
 			break
@@ -535,6 +540,9 @@ func listGroupMember(ctx *cli.Context) error {
 		initStartKey = memberList.Groups[memberNum-1].AccountID
 	}
 
+	if isStructuredOutput() {
+		return printOutput(output)
+	}
 	return nil
 }
 
@@ -553,6 +561,7 @@ func listGroup(ctx *cli.Context) error {
 	c, cancelListGroup := context.WithCancel(globalContext)
 	defer cancelListGroup()
 
+	output := make([]groupOutput, 0)
 	initStartKey := ""
 	for {
 		groupList, err := client.ListGroupsByOwner(c,
@@ -561,7 +570,11 @@ func listGroup(ctx *cli.Context) error {
 			return toCmdErr(err)
 		}
 
-		printListGroupResult(groupList)
+		if isStructuredOutput() {
+			output = append(output, toGroupOutput(groupList)...)
+		} else {
+			printListGroupResult(groupList)
+		}
 		memberNum := len(groupList.Groups)
 		if memberNum < maxListMemberNum {
 			break
@@ -571,6 +584,9 @@ func listGroup(ctx *cli.Context) error {
 		initStartKey = strconv.FormatUint(id.Uint64(), 10)
 	}
 
+	if isStructuredOutput() {
+		return printOutput(output)
+	}
 	return nil
 }
 
@@ -589,6 +605,7 @@ func listBelongGroup(ctx *cli.Context) error {
 	c, cancelListGroup := context.WithCancel(globalContext)
 	defer cancelListGroup()
 
+	output := make([]groupOutput, 0)
 	initStartKey := ""
 	for {
 		groupList, err := client.ListGroupsByAccount(c,
@@ -597,7 +614,11 @@ func listBelongGroup(ctx *cli.Context) error {
 			return toCmdErr(err)
 		}
 
-		printListGroupResult(groupList)
+		if isStructuredOutput() {
+			output = append(output, toGroupOutput(groupList)...)
+		} else {
+			printListGroupResult(groupList)
+		}
 		memberNum := len(groupList.Groups)
 		if memberNum < maxListMemberNum {
 			break
@@ -607,11 +628,14 @@ func listBelongGroup(ctx *cli.Context) error {
 		initStartKey = strconv.FormatUint(id.Uint64(), 10)
 	}
 
+	if isStructuredOutput() {
+		return printOutput(output)
+	}
 	return nil
 }
 
 func printListMemberResult(listResult *sdktypes.GroupMembersResult) {
-	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", len(iso8601DateFormat), operatorAddressLen, len(iso8601DateFormat))
+	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", timeColumnWidth(), operatorAddressLen, timeColumnWidth())
 	fmt.Printf(format, "create-time", "member", "expire-time")
 
 	for _, member := range listResult.Groups {
@@ -619,30 +643,55 @@ func printListMemberResult(listResult *sdktypes.GroupMembersResult) {
 			continue
 		}
 
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(member.CreateTime, 0).In(location)
-
 		expireTime, err := strconv.ParseInt(member.ExpirationTime, 10, 64)
 		if err != nil {
 			expireTime = 0
 		}
 
-		fmt.Printf(format, t.Format(iso8601DateFormat), member.AccountID, time.Unix(expireTime, 0).In(location).Format(iso8601DateFormat))
+		fmt.Printf(format, formatUnixTime(member.CreateTime), member.AccountID, formatUnixTime(expireTime))
 	}
 }
 
+func toGroupMemberOutput(listResult *sdktypes.GroupMembersResult) []groupMemberOutput {
+	output := make([]groupMemberOutput, 0, len(listResult.Groups))
+	for _, member := range listResult.Groups {
+		if member.Removed {
+			continue
+		}
+		output = append(output, groupMemberOutput{
+			Member:     member.AccountID,
+			CreateTime: formatOutputTime(member.CreateTime),
+			ExpireTime: formatExpireTime(member.ExpirationTime),
+		})
+	}
+	return output
+}
+
+func toGroupOutput(listResult *sdktypes.GroupsResult) []groupOutput {
+	output := make([]groupOutput, 0, len(listResult.Groups))
+	for _, group := range listResult.Groups {
+		if group.Removed {
+			continue
+		}
+		output = append(output, groupOutput{
+			GroupName:  group.Group.GroupName,
+			GroupID:    group.Group.Id.String(),
+			Owner:      group.Group.Owner,
+			CreateTime: formatOutputTime(group.CreateTime),
+		})
+	}
+	return output
+}
+
 func printListGroupResult(listResult *sdktypes.GroupsResult) {
-	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", len(iso8601DateFormat)+3, 20, 10)
+	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", timeColumnWidth()+3, 20, 10)
 	fmt.Printf(format, "create-time", "group-name", "id")
 
 	for _, group := range listResult.Groups {
 		if group.Removed {
 			continue
 		}
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(group.CreateTime, 0).In(location)
-
-		fmt.Printf(format, t.Format(iso8601DateFormat), group.Group.GroupName, strconv.FormatUint(group.Group.Id.Uint64(), 10))
+		fmt.Printf(format, formatUnixTime(group.CreateTime), group.Group.GroupName, strconv.FormatUint(group.Group.Id.Uint64(), 10))
 	}
 }
 
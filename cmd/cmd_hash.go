@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
 	"github.com/urfave/cli/v2"
+	"github.com/zkMeLabs/mechain-go-sdk/client"
 )
 
 // cmdMakeBucket create a new Bucket
@@ -53,8 +58,7 @@ func computeHashRoot(ctx *cli.Context) error {
 
 	hashes, size, _, err := gnfdClient.ComputeHashRoots(fReader, false)
 	if err != nil {
-		fmt.Println("compute hash root fail:", err.Error())
-		return toCmdErr(err)
+		return toCmdErr(fmt.Errorf("compute hash root fail: %v", err))
 	}
 
 	fmt.Printf("the primary sp hash root: \n%s\n%s\n", hex.EncodeToString(hashes[0]), "the secondary sp hash list:")
@@ -67,3 +71,57 @@ func computeHashRoot(ctx *cli.Context) error {
 
 	return nil
 }
+
+// computeFileHashRoot compute the integrity hash root of the primary sp for the local file
+func computeFileHashRoot(gnfdClient client.IClient, filePath string) ([]byte, error) {
+	fReader, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fReader.Close()
+
+	hashes, _, _, err := gnfdClient.ComputeHashRoots(fReader, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) == 0 {
+		return nil, errors.New("compute hash root fail: empty hash list")
+	}
+	return hashes[0], nil
+}
+
+// verifyDownloadedFile compare the integrity hash root of the downloaded file with the primary checksum of the object,
+// the file is removed if they do not match so that a corrupted payload will not be left
+func verifyDownloadedFile(gnfdClient client.IClient, filePath string, objectInfo *storageTypes.ObjectInfo) error {
+	if len(objectInfo.Checksums) == 0 {
+		return fmt.Errorf("no checksum found for object %s", objectInfo.ObjectName)
+	}
+
+	hashRoot, err := computeFileHashRoot(gnfdClient, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to verify the downloaded file %s: %v", filePath, err)
+	}
+
+	if !bytes.Equal(hashRoot, objectInfo.Checksums[0]) {
+		if err = os.Remove(filePath); err != nil {
+			return fmt.Errorf("%w, and failed to remove the file %s: %v", ErrChecksumMismatch, filePath, err)
+		}
+		return fmt.Errorf("%w, the file %s has been removed", ErrChecksumMismatch, filePath)
+	}
+	return nil
+}
+
+// computeFileSHA256 compute the sha256 hash of the local file in hex format
+func computeFileSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
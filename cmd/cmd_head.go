@@ -127,8 +127,11 @@ func headObject(ctx *cli.Context) error {
 
 	objectDetail, err := client.HeadObject(c, bucketName, objectName)
 	if err != nil {
-		fmt.Println("no such object")
-		return nil
+		return toCmdErr(ErrObjectNotExist)
+	}
+
+	if isStructuredOutput() {
+		return printProtoOutput(objectDetail)
 	}
 
 	fmt.Println("latest object info:")
@@ -136,9 +139,11 @@ func headObject(ctx *cli.Context) error {
 		if format == defaultFormat {
 			parseObjectInfo(objectDetail)
 		} else if format == jsonFormat {
-			parseByJsonFormat(objectDetail)
+			if err = parseByJsonFormat(objectDetail); err != nil {
+				return toCmdErr(err)
+			}
 		} else {
-			return toCmdErr(fmt.Errorf("invalid format"))
+			return toCmdErr(invalidArgsError("invalid format"))
 		}
 	}
 	return nil
@@ -160,13 +165,15 @@ func headBucket(ctx *cli.Context) error {
 
 	bucketInfo, err := client.HeadBucket(c, bucketName)
 	if err != nil {
-		fmt.Println("no such bucket")
-		return nil
+		return toCmdErr(ErrBucketNotExist)
 	}
 	gvgf, err := client.QueryVirtualGroupFamily(ctx.Context, bucketInfo.GlobalVirtualGroupFamilyId)
 	if err != nil {
-		fmt.Println("no such virtual group family:", bucketInfo.GlobalVirtualGroupFamilyId)
-		return err
+		return toCmdErr(fmt.Errorf("no such virtual group family %d: %v", bucketInfo.GlobalVirtualGroupFamilyId, err))
+	}
+
+	if isStructuredOutput() {
+		return printProtoOutput(bucketInfo)
 	}
 
 	fmt.Println("latest bucket info:")
@@ -174,9 +181,11 @@ func headBucket(ctx *cli.Context) error {
 		if format == defaultFormat {
 			parseBucketInfo(bucketInfo, gvgf)
 		} else if format == jsonFormat {
-			parseByJsonFormat(bucketInfo)
+			if err = parseByJsonFormat(bucketInfo); err != nil {
+				return toCmdErr(err)
+			}
 		} else {
-			return toCmdErr(fmt.Errorf("invalid format"))
+			return toCmdErr(invalidArgsError("invalid format"))
 		}
 	}
 	return nil
@@ -203,8 +212,11 @@ func headGroup(ctx *cli.Context) error {
 
 	groupInfo, err := client.HeadGroup(c, groupName, groupOwner)
 	if err != nil {
-		fmt.Println("no such group")
-		return nil
+		return toCmdErr(ErrGroupNotExist)
+	}
+
+	if isStructuredOutput() {
+		return printProtoOutput(groupInfo)
 	}
 
 	fmt.Println("latest group info:")
@@ -215,9 +227,11 @@ func headGroup(ctx *cli.Context) error {
 				fmt.Println(info)
 			}
 		} else if format == jsonFormat {
-			parseByJsonFormat(groupInfo)
+			if err = parseByJsonFormat(groupInfo); err != nil {
+				return toCmdErr(err)
+			}
 		} else {
-			return toCmdErr(fmt.Errorf("invalid format"))
+			return toCmdErr(invalidArgsError("invalid format"))
 		}
 	}
 
@@ -226,7 +240,7 @@ func headGroup(ctx *cli.Context) error {
 
 func headGroupMember(ctx *cli.Context) error {
 	if ctx.NArg() != 2 {
-		return toCmdErr(fmt.Errorf("args number should be 2"))
+		return toCmdErr(invalidArgsError("args number should be 2"))
 	}
 
 	// read the head member address
@@ -247,6 +261,9 @@ func headGroupMember(ctx *cli.Context) error {
 	}
 
 	exist := client.HeadGroupMember(c, groupName, groupOwner, headMember)
+	if isStructuredOutput() {
+		return printOutput(groupMemberCheckOutput{Member: headMember, GroupName: groupName, IsMember: exist})
+	}
 	if !exist {
 		fmt.Println("the user does not exist in the group")
 		return nil
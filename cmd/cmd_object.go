@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -39,9 +42,17 @@ Note that the  uploading with recursive flag only support folder.
 Examples:
 # create object and upload file to storage provider, the corresponding object is mechain-object
 $ mechain-cmd object put file.txt mechain://mechain-bucket/mechain-object,
+# upload the payload read from stdin as the object
+$ tar c dir | mechain-cmd object put - mechain://mechain-bucket/backup.tar
 # upload the files inside the folders
-$ mechain-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2","value":"value2"}]' --recursive folderName mechain://bucket-name`,
-		Flags: []cli.Flag{
+$ mechain-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2","value":"value2"}]' --recursive folderName mechain://bucket-name
+# upload multiple files to the bucket, 8 files at the same time
+$ mechain-cmd object put --parallel 8 file1.txt file2.txt file3.txt mechain://bucket-name
+# upload the files inside the folders, 8 objects at the same time
+$ mechain-cmd object put --recursive --parallel 8 folderName mechain://bucket-name
+# upload the folder except the .git folder and the log files, the .mechainignore file in the folder is also applied
+$ mechain-cmd object put --recursive --exclude .git --exclude "*.log" folderName mechain://bucket-name`,
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:  secondarySPFlag,
 				Value: "",
@@ -89,7 +100,23 @@ $ mechain-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2",
 				Value: "",
 				Usage: "set one or more tags of the object. The tag value is key-value pairs in json array format. E.g. [{\"key\":\"key1\",\"value\":\"value1\"},{\"key\":\"key2\",\"value\":\"value2\"}]",
 			},
-		},
+			&cli.Int64Flag{
+				Name:  sizeFlag,
+				Value: 0,
+				Usage: "the size of the payload when uploading from stdin with \"-\" as the file path, the upload fails if the size read from stdin is different",
+			},
+			&cli.IntFlag{
+				Name:  parallelFlag,
+				Value: 1,
+				Usage: "the number of objects to be created and uploaded at the same time when uploading a folder with recursive flag, " +
+					"the multiple files are uploaded 4 at the same time if it is not set",
+			},
+			&cli.BoolFlag{
+				Name:  recordHashFlag,
+				Value: false,
+				Usage: "record the sha256 hash of every file when uploading a folder with recursive flag, so that the changed files can be detected exactly on task retry",
+			},
+		}, filterFlags()...),
 	}
 }
 
@@ -105,8 +132,14 @@ Download a specific object from storage provider
 
 Examples:
 # download an object payload to file
-$ mechain-cmd object get mechain://mechain-bucket/mechain-object  file.txt `,
-		Flags: []cli.Flag{
+$ mechain-cmd object get mechain://mechain-bucket/mechain-object  file.txt 
+# download an object payload to stdout
+$ mechain-cmd object get mechain://mechain-bucket/backup.tar - | tar x
+# download all the objects under the prefix to the local folder
+$ mechain-cmd object get --recursive mechain://mechain-bucket/prefix/  ./folder 
+# download the jpg objects under the prefix to the local folder
+$ mechain-cmd object get --recursive --include "*.jpg" mechain://mechain-bucket/prefix/  ./folder `,
+		Flags: append([]cli.Flag{
 			&cli.Int64Flag{
 				Name:  startOffsetFlag,
 				Value: 0,
@@ -136,7 +169,22 @@ $ mechain-cmd object get mechain://mechain-bucket/mechain-object  file.txt `,
 				Value: "",
 				Usage: "indicate object sp endpoint",
 			},
-		},
+			&cli.BoolFlag{
+				Name:  recursiveFlag,
+				Value: false,
+				Usage: "download all the objects under the specified prefix in a recursive way and rebuild the folder tree locally",
+			},
+			&cli.BoolFlag{
+				Name:  skipVerifyFlag,
+				Value: false,
+				Usage: "skip verifying the downloaded file against the checksum of the object on chain",
+			},
+			&cli.IntFlag{
+				Name:  parallelFlag,
+				Value: 1,
+				Usage: "the number of objects to be downloaded at the same time with recursive flag",
+			},
+		}, filterFlags()...),
 	}
 }
 
@@ -165,15 +213,49 @@ func cmdListObjects() *cli.Command {
 		Description: `
 List Objects of the bucket, including object name, object id, object status
 
+The long listing shows the create time, the size, the visibility, the status, the content type, the owner, the object id,
+the prefix of the primary checksum, the tags and the name of every object. The removed objects are shown with the removed
+status if --show-removed is set. The objects are sorted by name, by size from the largest or by time from the newest
+if --sortBy is set, otherwise they are shown in the order of listing.
+
 Examples:
-$ mechain-cmd object ls mechain://mechain-bucket`,
-		Flags: []cli.Flag{
+$ mechain-cmd object ls mechain://mechain-bucket
+# list the objects except the log files in a recursive way
+$ mechain-cmd object ls --recursive --exclude "*.log" mechain://mechain-bucket
+# audit the objects of the bucket including the removed ones, the largest first
+$ mechain-cmd object ls -l --human-readable --show-removed --sortBy size --recursive mechain://mechain-bucket`,
+		Flags: append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:  recursiveFlag,
 				Value: false,
 				Usage: "performed on all files or objects under the specified directory or prefix in a recursive way",
 			},
-		},
+			&cli.BoolFlag{
+				Name:    longFlag,
+				Aliases: []string{"l"},
+				Value:   false,
+				Usage:   "show the visibility, the status, the content type, the owner, the object id, the checksum and the tags of the objects",
+			},
+			&cli.BoolFlag{
+				Name:    humanReadableFlag,
+				Aliases: []string{"human-readable"},
+				Value:   false,
+				Usage:   "show the sizes in K, M and G",
+			},
+			&cli.BoolFlag{
+				Name:    showRemovedFlag,
+				Aliases: []string{"show-removed"},
+				Value:   false,
+				Usage:   "list the removed objects as well",
+			},
+			&cli.GenericFlag{
+				Name: sortByFlag,
+				Value: &CmdEnumValue{
+					Enum: []string{objectSortByName, objectSortBySize, objectSortByTime},
+				},
+				Usage: "sort the objects by the name, the size or the create time",
+			},
+		}, filterFlags()...),
 	}
 }
 
@@ -290,7 +372,7 @@ $ mechain-cmd object setTag --tags='[{"key":"key1","value":"value1"},{"key":"key
 // setTag Set tag for a given existing object
 func setTagForObject(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be one"))
+		return toCmdErr(invalidArgsError("args number should be one"))
 	}
 
 	urlInfo := ctx.Args().First()
@@ -337,7 +419,7 @@ func setTagForObject(ctx *cli.Context) error {
 // putObject upload the payload of file, finish the third stage of putObject
 func putObject(ctx *cli.Context) error {
 	if ctx.NArg() < 1 {
-		return toCmdErr(fmt.Errorf("args number error"))
+		return toCmdErr(invalidArgsError("args number error"))
 	}
 
 	var (
@@ -368,6 +450,7 @@ func putObject(ctx *cli.Context) error {
 		}
 
 		if err = uploadFile(bucketName, objectName, filePath, urlInfo, ctx, gnfdClient, isUploadSingleFolder, true, 0); err != nil {
+			emitObjectEvent(progressEventFailed, "", bucketName, objectName, err)
 			return toCmdErr(err)
 		}
 
@@ -397,39 +480,31 @@ func putObject(ctx *cli.Context) error {
 			urlInfo = ctx.Args().Get(argNum - 1)
 			bucketName = ParseBucket(urlInfo)
 			if bucketName == "" {
-				return toCmdErr(errors.New("fail to parse bucket name"))
+				return toCmdErr(invalidArgsError("fail to parse bucket name"))
 			}
 
-			for idx, fileName := range filePathList {
-				nameList := strings.Split(fileName, "/")
-				objectName = nameList[len(nameList)-1]
-				objectSize, err = parseFileByArg(ctx, idx)
-				if err != nil {
-					return toCmdErr(err)
-				}
-
-				if err = uploadFile(bucketName, objectName, fileName, urlInfo, ctx, gnfdClient, false, true, objectSize); err != nil {
-					fmt.Println("upload object:", objectName, "err", err)
-				}
-				fmt.Println()
-			}
+			return uploadMultiFiles(ctx, gnfdClient, bucketName, urlInfo, filePathList)
 		} else {
 			// upload single file
+			urlInfo = ctx.Args().Get(1)
+			if filePathList[0] == stdioPath {
+				return uploadFromStdin(ctx, gnfdClient, urlInfo)
+			}
 			objectSize, err = parseFileByArg(ctx, 0)
 			if err != nil {
 				return toCmdErr(err)
 			}
-			urlInfo = ctx.Args().Get(1)
 			bucketName, objectName, err = getObjAndBucketNames(urlInfo)
 			if err != nil {
 				bucketName = ParseBucket(urlInfo)
 				if bucketName == "" {
-					return toCmdErr(errors.New("fail to parse bucket name"))
+					return toCmdErr(invalidArgsError("fail to parse bucket name"))
 				}
 				// if the object name has not been set, set the file name as object name
 				objectName = filepath.Base(filePathList[0])
 			}
 			if err = uploadFile(bucketName, objectName, filePathList[0], urlInfo, ctx, gnfdClient, false, true, objectSize); err != nil {
+				emitObjectEvent(progressEventFailed, "", bucketName, objectName, err)
 				return toCmdErr(err)
 			}
 		}
@@ -438,6 +513,116 @@ func putObject(ctx *cli.Context) error {
 	return nil
 }
 
+// uploadFromStdin upload the payload read from stdin as the object. Since the integrity hash needs to be computed
+// before creating the object, the payload is spooled to a temp file firstly.
+func uploadFromStdin(ctx *cli.Context, gnfdClient client.IClient, urlInfo string) error {
+	bucketName, objectName, err := getObjAndBucketNames(urlInfo)
+	if err != nil {
+		return toCmdErr(invalidArgsError("the object name should be set when uploading from stdin"))
+	}
+
+	expectSize := ctx.Int64(sizeFlag)
+	if expectSize > int64(maxFileSize) {
+		return toCmdErr(fmt.Errorf("upload file larger than 64G "))
+	}
+
+	tempFile, err := os.CreateTemp("", ".mechain-cmd-stdin-*")
+	if err != nil {
+		return toCmdErr(err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	// read one more byte than the limit to find out whether stdin is longer than expected
+	limit := int64(maxFileSize) + 1
+	if expectSize > 0 {
+		limit = expectSize + 1
+	}
+
+	fmt.Println("reading the payload from stdin...")
+	objectSize, err := io.Copy(tempFile, io.LimitReader(os.Stdin, limit))
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if expectSize > 0 && objectSize != expectSize {
+		return toCmdErr(fmt.Errorf("the size of the payload read from stdin does not match the size %d", expectSize))
+	}
+	if objectSize > int64(maxFileSize) {
+		return toCmdErr(fmt.Errorf("upload file larger than 64G "))
+	}
+	if err = tempFile.Close(); err != nil {
+		return toCmdErr(err)
+	}
+
+	if err = uploadFile(bucketName, objectName, tempFile.Name(), urlInfo, ctx, gnfdClient, false, true, objectSize); err != nil {
+		emitObjectEvent(progressEventFailed, "", bucketName, objectName, err)
+		return toCmdErr(err)
+	}
+	return nil
+}
+
+// uploadMultiFiles upload the files to the bucket concurrently and print the summary of the results,
+// it returns an error if any of the files failed to upload
+func uploadMultiFiles(ctx *cli.Context, gnfdClient client.IClient, bucketName, urlInfo string, filePathList []string) error {
+	// the default of the flag is for the folder task, the multiple files are uploaded concurrently unless it is set
+	parallel := defaultParallelNum
+	if ctx.IsSet(parallelFlag) {
+		parallel = ctx.Int(parallelFlag)
+	}
+	// the detail of every upload is only printed when the files are uploaded one by one
+	verbose := parallel <= 1
+
+	results := make([]*transferResult, len(filePathList))
+	pool := NewPool(parallel)
+	for idx, fileName := range filePathList {
+		nameList := strings.Split(fileName, "/")
+		results[idx] = &transferResult{
+			Name: nameList[len(nameList)-1],
+			Path: fileName,
+		}
+
+		pool.Add(1)
+		go func(idx int, result *transferResult) {
+			defer pool.Done()
+			objectSize, err := parseFileByArg(ctx, idx)
+			if err != nil {
+				result.fail(err)
+				return
+			}
+			result.Size = objectSize
+
+			objectDetail, err := gnfdClient.HeadObject(globalContext, bucketName, result.Name)
+			if err == nil && objectDetail.ObjectInfo.GetObjectStatus() == storageTypes.OBJECT_STATUS_SEALED {
+				result.Status, result.Comment = transferStatusSkipped, "object already exists"
+				return
+			}
+
+			if err = uploadFile(bucketName, result.Name, result.Path, urlInfo, ctx, gnfdClient, false, verbose, objectSize); err != nil {
+				result.fail(err)
+				fmt.Println("upload object:", result.Name, "err", err)
+				return
+			}
+			result.Status = transferStatusSuccess
+			if verbose {
+				fmt.Println()
+			}
+		}(idx, results[idx])
+	}
+	pool.Wait()
+
+	printTransferSummary(results)
+	var failedNum int
+	for _, result := range results {
+		if result.Status == transferStatusFailed {
+			failedNum++
+		}
+	}
+	if failedNum > 0 {
+		return fmt.Errorf("%d of %d files failed to upload", failedNum, len(results))
+	}
+	return nil
+}
+
 // uploadFolder upload folder and the files inside to bucket in a recursive way
 func uploadFolder(urlInfo string, ctx *cli.Context,
 	gnfdClient client.IClient,
@@ -465,37 +650,88 @@ func uploadFolder(urlInfo string, ctx *cli.Context,
 
 	taskID := uuid.New().String()
 
-	fmt.Println("================================================")
-	fmt.Println("Your batch upload is submitted as a task, task ID is " + taskID)
-	fmt.Println("You can check your task status and progress by using cmd as below:\n\n- List all your tasks: ./mechain-cmd task ls\n- Check status: ./mechain-cmd task status --task.id taskID\n- Retry (in case this process is killed accidentally): ./mechain-cmd task retry --task.id taskID\n- Delete task: ...\n\n>>================================================")
+	printTaskSubmitted(TaskKindUpload, taskID)
 	fmt.Println("Upload Task building")
 
 	taskState := &TaskState{
 		Lock:        new(sync.Mutex),
 		ObjectState: make(map[int]*UploadTaskObject),
 		TaskID:      taskID,
+		Kind:        TaskKindUpload,
 		FolderName:  folderName,
 		BucketName:  bucketName,
 		Status:      TaskStatusCreate,
+		CreateTime:  time.Now().Unix(),
+	}
+
+	taskState.FilterFlag = getFilterFlag(ctx, filepath.Join(folderName, defaultIgnoreFileName))
+	taskState.RecordHash = ctx.Bool(recordHashFlag)
+	taskState.ObjectState, err = buildUploadTaskObjects(folderName, bucketName, taskState.FilterFlag, taskState.RecordHash)
+	if err != nil {
+		return err
+	}
+
+	// store tag
+	taskState.Flag, err = getUploadFlag(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = saveTaskState(homeDir, taskState); err != nil {
+		return err
+	}
+
+	return uploadFolderByTask(ctx, homeDir, gnfdClient, taskState)
+}
+
+// buildUploadTaskObjects walk the folder and build the objects of the upload task, the folder objects are followed by the files.
+// The modification time and the size of the files are recorded, as well as the sha256 hash if recordHash is true
+func buildUploadTaskObjects(folderName, bucketName string, filterFlag FilterFlag, recordHash bool) (map[int]*UploadTaskObject, error) {
+	filter, err := newObjectFilterByFlag(filterFlag)
+	if err != nil {
+		return nil, err
 	}
 
 	baseDir := filepath.Base(folderName)
 
-	fileInfos := make([]os.FileInfo, 0)
-	filePaths := make([]string, 0)
-	objectNames := make([]string, 0)
+	objectState := make(map[int]*UploadTaskObject)
+	fileObjects := make([]*UploadTaskObject, 0)
 	objectIndex := 0
 
 	listFolderErr := filepath.Walk(folderName, func(path string, info os.FileInfo, err error) error {
+		relPath, err := filepath.Rel(folderName, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if info.IsDir() && filter.excluded(relPath, true) {
+			return filepath.SkipDir
+		}
+		if !filter.match(relPath, info.IsDir()) {
+			return nil
+		}
+
 		if !info.IsDir() {
-			fileInfos = append(fileInfos, info)
 			// use the base dir to construct object name of the file
 			index := strings.Index(path, baseDir)
 			if index == notFound {
 				return nil
 			}
-			objectNames = append(objectNames, path[index:])
-			filePaths = append(filePaths, path)
+			utj := &UploadTaskObject{
+				BucketName:         bucketName,
+				ObjectName:         path[index:],
+				FilePath:           path,
+				UploadSingleFolder: false,
+				ObjectSize:         info.Size(),
+				Status:             TaskObjectStatusWaitForUpload,
+				ModTime:            info.ModTime().UnixNano(),
+			}
+			if recordHash {
+				if utj.FileHash, err = computeFileSHA256(path); err != nil {
+					return err
+				}
+			}
+			fileObjects = append(fileObjects, utj)
 		} else {
 			// use the base dir to construct object name of the sub-folder
 			index := strings.Index(path, baseDir)
@@ -511,159 +747,173 @@ func uploadFolder(urlInfo string, ctx *cli.Context,
 				ObjectSize:         0,
 				Status:             TaskObjectStatusWaitForUpload,
 			}
-			taskState.ObjectState[objectIndex] = utj
+			objectState[objectIndex] = utj
 			objectIndex++
 		}
 		return nil
 	})
 
 	if listFolderErr != nil {
-		return listFolderErr
+		return nil, listFolderErr
 	}
 	// upload folder
-	for id, info := range fileInfos {
-		utj := &UploadTaskObject{
-			BucketName:         bucketName,
-			ObjectName:         objectNames[id],
-			FilePath:           filePaths[id],
-			UploadSingleFolder: false,
-			ObjectSize:         info.Size(),
-			Status:             TaskObjectStatusWaitForUpload,
-		}
-		taskState.ObjectState[objectIndex] = utj
+	for _, utj := range fileObjects {
+		objectState[objectIndex] = utj
 		objectIndex++
 	}
+	return objectState, nil
+}
 
-	// store tag
-	contentType := ctx.String(contentTypeFlag)
-	secondarySPAccs := ctx.String(secondarySPFlag)
-	partSize := ctx.Uint64(partSizeFlag)
-	tags := ctx.String(tagFlag)
-	visibility := ctx.Generic(visibilityFlag)
-
-	taskState.Flag = UploadFlag{
-		ContentType: contentType,
-		SecondarySP: secondarySPAccs,
-		PartSize:    partSize,
-		Tags:        tags,
+// getUploadFlag parse the upload options of the command which are shared by all the objects of a batch upload
+func getUploadFlag(ctx *cli.Context) (UploadFlag, error) {
+	uploadFlag := UploadFlag{
+		ContentType: ctx.String(contentTypeFlag),
+		SecondarySP: ctx.String(secondarySPFlag),
+		PartSize:    ctx.Uint64(partSizeFlag),
+		Tags:        ctx.String(tagFlag),
 		Visibility:  storageTypes.VISIBILITY_TYPE_INHERIT,
 	}
 
+	visibility := ctx.Generic(visibilityFlag)
 	if visibility != "" {
 		visibilityTypeVal, typeErr := getVisibilityType(fmt.Sprintf("%s", visibility))
 		if typeErr != nil {
-			return typeErr
+			return uploadFlag, typeErr
 		}
-		taskState.Flag.Visibility = visibilityTypeVal
-	}
-
-	taskFileName := fmt.Sprintf("/.%s/state", taskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
-
-	content, err := json.Marshal(taskState)
-	if err != nil {
-		return toCmdErr(err)
+		uploadFlag.Visibility = visibilityTypeVal
 	}
+	return uploadFlag, nil
+}
 
-	err = createAndWriteFile(taskFilePath, content)
+func uploadFolderByTask(ctx *cli.Context, homeDir string, gnfdClient client.IClient, taskState *TaskState) error {
+	finishTask, err := startTask(homeDir, taskState)
 	if err != nil {
 		return err
 	}
+	defer finishTask()
 
-	return uploadFolderByTask(ctx, homeDir, gnfdClient, taskState)
-}
-
-func uploadFolderByTask(ctx *cli.Context, homeDir string, gnfdClient client.IClient, taskState *TaskState) error {
 	signalCtx, cancel := context.WithCancel(ctx.Context)
-	go stateSync(signalCtx, homeDir, taskState)
+	syncDone := make(chan struct{})
+	go func() {
+		stateSync(signalCtx, homeDir, taskState)
+		close(syncDone)
+	}()
 	sealSignal := make(chan int)
 	go sealChecker(ctx, taskState, gnfdClient, sealSignal)
 
+	// the objects are created and uploaded concurrently, the number of running uploads is limited by the pool
+	pool := NewPool(ctx.Int(parallelFlag))
+	silent := silentProgressBar(ctx.Int(parallelFlag))
 	for index, object := range taskState.ObjectState {
 		// check object status
-		if object.Status == TaskObjectStatusCreated || object.Status == TaskObjectStatusSeal {
+		if status := taskState.GetObjectStatus(index); status == TaskObjectStatusCreated || status == TaskObjectStatusSeal {
 			continue
 		}
 
-		err := uploadFileByTask(object.BucketName, object.ObjectName, object.FilePath, taskState.Flag, gnfdClient, object.UploadSingleFolder, object.ObjectSize)
-		if err != nil {
-			taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
-			fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusFailed, object.ObjectName, err.Error()))
-		} else {
-			taskState.UpdateObjectState(index, TaskObjectStatusCreated, "")
-			fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusCreated, object.ObjectName, ""))
-		}
+		pool.Add(1)
+		go func(index int, object *UploadTaskObject) {
+			defer pool.Done()
+			taskState.StartObject(index)
+			txnHash, err := uploadFileByTask(taskState.TaskID, object.BucketName, object.ObjectName, object.FilePath, taskState.Flag, gnfdClient, object.UploadSingleFolder, object.ObjectSize, silent)
+			if err != nil {
+				taskState.FinishObject(index, txnHash, 0)
+				taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
+				emitObjectEvent(progressEventFailed, taskState.TaskID, object.BucketName, object.ObjectName, err)
+				fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusFailed, object.ObjectName, err.Error()))
+			} else {
+				taskState.FinishObject(index, txnHash, object.ObjectSize)
+				taskState.UpdateObjectState(index, TaskObjectStatusCreated, "")
+				fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusCreated, object.ObjectName, ""))
+			}
+		}(index, object)
 	}
+	pool.Wait()
 
 	// waiting for seal
 	<-sealSignal
 	cancel()
 	// waiting sync status
-	time.Sleep(5 * time.Second)
+	<-syncDone
 	fmt.Println()
+	taskState.Lock.Lock()
 	if taskState.Status != TaskStatusSuccess {
 		taskState.Status = TaskStatusFail
 	}
-	content, err := json.Marshal(taskState)
-	if err != nil {
+	succeeded := taskState.Status == TaskStatusSuccess
+	taskState.Lock.Unlock()
+	if err := saveTaskState(homeDir, taskState); err != nil {
 		return toCmdErr(err)
 	}
-	taskFileName := fmt.Sprintf("/.%s/state", taskState.TaskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
-	if err = createAndWriteFile(taskFilePath, content); err != nil {
-		return toCmdErr(err)
+	emitTaskDone(taskState)
+	if !succeeded {
+		return taskFailedError(taskState)
 	}
+	applyTaskRetention(ctx, homeDir, taskState.TaskID)
 	return nil
 }
 
-// sealChecker Wait for seal and update the status
+// sealChecker Wait for seal and update the status. All the created objects are checked on every tick, so that
+// the seal time of every object is recorded when it is sealed rather than after the objects before it
 func sealChecker(ctx *cli.Context, taskState *TaskState, gnfdClient client.IClient, signal chan int) {
 	tick := time.NewTicker(3 * time.Second)
 	defer tick.Stop()
-	objectOffset := 0
 	for range tick.C {
-		if objectOffset >= len(taskState.ObjectState) {
-			break
-		}
-		utj := taskState.ObjectState[objectOffset]
-		headObjOutput, queryErr := gnfdClient.HeadObject(ctx.Context, utj.BucketName, utj.ObjectName)
-		if queryErr != nil {
-			continue
-		}
+		finished := true
+		for index := 0; index < len(taskState.ObjectState); index++ {
+			switch taskState.GetObjectStatus(index) {
+			case TaskObjectStatusSeal, TaskObjectStatusFailed:
+				// the failed object will never be sealed, no need to wait for it
+				continue
+			case TaskObjectStatusCreated:
+			default:
+				// the object is not created yet
+				finished = false
+				continue
+			}
 
-		if headObjOutput.ObjectInfo.GetObjectStatus().String() == "OBJECT_STATUS_SEALED" {
-			taskState.UpdateObjectState(objectOffset, TaskObjectStatusSeal, "")
+			utj := taskState.ObjectState[index]
+			headObjOutput, queryErr := gnfdClient.HeadObject(ctx.Context, utj.BucketName, utj.ObjectName)
+			if queryErr != nil || headObjOutput.ObjectInfo.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+				finished = false
+				continue
+			}
+			taskState.SealObject(index)
+			taskState.UpdateObjectState(index, TaskObjectStatusSeal, "")
+			emitObjectEvent(progressEventSealed, taskState.TaskID, utj.BucketName, utj.ObjectName, nil)
 			fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusSeal, utj.ObjectName, ""))
-			objectOffset++
+		}
+
+		if finished {
+			break
 		}
 	}
 
-	taskState.Status = TaskStatusSuccess
+	taskStatus := TaskStatusSuccess
+	if taskState.CountObjectStatus()[TaskObjectStatusFailed] > 0 {
+		taskStatus = TaskStatusFail
+	}
+	taskState.SetStatus(taskStatus)
 	signal <- 1
 }
 
 // stateSync Periodically synchronize the status to the local file
 func stateSync(ctx context.Context, homeDir string, state *TaskState) {
-	taskFileName := fmt.Sprintf("/.%s/state", state.TaskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
 	tick := time.NewTicker(500 * time.Millisecond)
 	defer tick.Stop()
 	for {
 		select {
 		case <-tick.C:
-			content, err := json.Marshal(state)
-			if err != nil {
-				continue
-			}
-			_ = createAndWriteFile(taskFilePath, content)
+			_ = saveTaskState(homeDir, state)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// uploadFile create the object on chain and upload the payload to SP, verbose indicates whether to print
+// the transaction hash, the uploading progress and the sealing status
 func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Context,
-	gnfdClient client.IClient, uploadSingleFolder, printTxnHash bool, objectSize int64,
+	gnfdClient client.IClient, uploadSingleFolder, verbose bool, objectSize int64,
 ) error {
 	var file *os.File
 	contentType := ctx.String(contentTypeFlag)
@@ -679,7 +929,7 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 		opts.Tags = &storageTypes.ResourceTags{}
 		err := json.Unmarshal([]byte(tags), &opts.Tags.Tags)
 		if err != nil {
-			return toCmdErr(err)
+			return err
 		}
 	}
 
@@ -722,7 +972,7 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 		if uploadSingleFolder {
 			txnHash, err = gnfdClient.CreateFolder(c, bucketName, objectName, opts)
 			if err != nil {
-				return toCmdErr(err)
+				return err
 			}
 		} else {
 			// Open the referenced file.
@@ -733,18 +983,18 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 			defer file.Close()
 			txnHash, err = gnfdClient.CreateObject(c, bucketName, objectName, file, opts)
 			if err != nil {
-				return toCmdErr(err)
+				return err
 			}
 			if err = waitTxnStatus(gnfdClient, c, txnHash, "createObject"); err != nil {
-				return toCmdErr(err)
+				return err
 			}
 		}
-		if printTxnHash {
+		if verbose {
 			fmt.Printf("object %s created on chain \n", objectName)
 			fmt.Println("transaction hash: ", txnHash)
 		}
 
-	} else {
+	} else if verbose {
 		fmt.Printf("object %s already exist \n", objectName)
 	}
 
@@ -772,28 +1022,35 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 		opt.DisableResumable = false
 	}
 
+	// the progress is not printed if it is not verbose, but the payload is still read through the progress reader to limit the rate
 	progressReader := &ProgressReader{
 		Reader:      reader,
+		Name:        objectName,
+		Bucket:      bucketName,
 		Total:       objectSize,
 		StartTime:   time.Now(),
 		LastPrinted: time.Now(),
+		Silent:      !verbose,
 	}
 
 	// if print big file progress, the printing progress should be delayed to obtain a more accurate display.
 	if objectSize > progressDelayPrintSize {
 		progressReader.LastPrinted = time.Now().Add(3 * time.Second)
 	}
+	var payload io.Reader = progressReader
 
 	if opt.DisableResumable {
 		if err = gnfdClient.PutObject(c, bucketName, objectName,
-			objectSize, progressReader, opt); err != nil {
-			return toCmdErr(err)
+			objectSize, payload, opt); err != nil {
+			return err
 		}
 	} else {
-		fmt.Printf("resumable uploading %s is beginning...\n", objectName)
+		if verbose {
+			fmt.Printf("resumable uploading %s is beginning...\n", objectName)
+		}
 		if err = gnfdClient.PutObject(c, bucketName, objectName,
-			objectSize, progressReader, opt); err != nil {
-			return toCmdErr(err)
+			objectSize, payload, opt); err != nil {
+			return err
 		}
 	}
 
@@ -806,23 +1063,26 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 	timeout := time.After(1 * time.Hour)
 	ticker := time.NewTicker(3 * time.Second)
 	count := 0
-	fmt.Println()
-	fmt.Println("sealing...")
+	if verbose {
+		fmt.Println()
+		fmt.Println("sealing...")
+	}
 	for {
 		select {
 		case <-timeout:
-			return toCmdErr(errors.New("object not sealed after one hour"))
+			return errors.New("object not sealed after one hour")
 		case <-ticker.C:
 			count++
 			headObjOutput, queryErr := gnfdClient.HeadObject(c, bucketName, objectName)
 			if queryErr != nil {
 				return queryErr
 			}
-			if count%10 == 0 {
+			if verbose && count%10 == 0 {
 				fmt.Println("sealing...")
 			}
 			if headObjOutput.ObjectInfo.GetObjectStatus().String() == "OBJECT_STATUS_SEALED" {
 				ticker.Stop()
+				emitObjectEvent(progressEventSealed, "", bucketName, objectName, nil)
 				fmt.Printf("upload %s to %s \n", objectName, urlInfo)
 				return nil
 			}
@@ -830,9 +1090,33 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 	}
 }
 
-func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag UploadFlag,
-	gnfdClient client.IClient, uploadSingleFolder bool, objectSize int64,
-) error {
+// waitObjectSealed query the object status periodically until the object is sealed or the timeout is reached
+func waitObjectSealed(c context.Context, gnfdClient client.IClient, bucketName, objectName string, timeout time.Duration) error {
+	timer := time.After(timeout)
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timer:
+			return fmt.Errorf("object %s not sealed after %s", objectName, timeout)
+		case <-ticker.C:
+			headObjOutput, err := gnfdClient.HeadObject(c, bucketName, objectName)
+			if err != nil {
+				return err
+			}
+			if headObjOutput.ObjectInfo.GetObjectStatus() == storageTypes.OBJECT_STATUS_SEALED {
+				emitObjectEvent(progressEventSealed, "", bucketName, objectName, nil)
+				return nil
+			}
+		}
+	}
+}
+
+// uploadFileByTask create and upload the object of the task, silent indicates the progress bar is not printed
+// since the objects are uploaded in parallel
+func uploadFileByTask(taskID, bucketName, objectName, filePath string, uploadFlag UploadFlag,
+	gnfdClient client.IClient, uploadSingleFolder bool, objectSize int64, silent bool,
+) (string, error) {
 	var file *os.File
 
 	opts := sdktypes.CreateObjectOptions{}
@@ -841,7 +1125,7 @@ func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag Upload
 		opts.Tags = &storageTypes.ResourceTags{}
 		err := json.Unmarshal([]byte(uploadFlag.Tags), &opts.Tags.Tags)
 		if err != nil {
-			return toCmdErr(err)
+			return "", err
 		}
 	}
 
@@ -871,32 +1155,33 @@ func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag Upload
 	defer cancelPutObject()
 
 	_, err := gnfdClient.HeadObject(c, bucketName, objectName)
+	var txnHash string
 	// if err==nil, object exist on chain, no need to createObject
 	if err != nil {
 		if uploadSingleFolder {
-			_, err = gnfdClient.CreateFolder(c, bucketName, objectName, opts)
+			txnHash, err = gnfdClient.CreateFolder(c, bucketName, objectName, opts)
 			if err != nil {
-				return toCmdErr(err)
+				return "", err
 			}
 		} else {
 			// Open the referenced file.
 			file, err = os.Open(filePath)
 			if err != nil {
-				return err
+				return "", err
 			}
 			defer file.Close()
-			txnHash, err := gnfdClient.CreateObject(c, bucketName, objectName, file, opts)
+			txnHash, err = gnfdClient.CreateObject(c, bucketName, objectName, file, opts)
 			if err != nil {
-				return toCmdErr(err)
+				return "", err
 			}
 			if err = waitTxnStatus(gnfdClient, c, txnHash, "createObject"); err != nil {
-				return toCmdErr(err)
+				return txnHash, err
 			}
 		}
 	}
 
 	if objectSize == 0 {
-		return nil
+		return txnHash, nil
 	}
 
 	opt := sdktypes.PutObjectOptions{}
@@ -909,7 +1194,7 @@ func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag Upload
 	// Open the referenced file.
 	reader, err := os.Open(filePath)
 	if err != nil {
-		return err
+		return txnHash, err
 	}
 	defer reader.Close()
 
@@ -920,9 +1205,13 @@ func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag Upload
 
 	progressReader := &ProgressReader{
 		Reader:      reader,
+		Name:        objectName,
+		TaskID:      taskID,
+		Bucket:      bucketName,
 		Total:       objectSize,
 		StartTime:   time.Now(),
 		LastPrinted: time.Now(),
+		Silent:      silent,
 	}
 
 	// if print big file progress, the printing progress should be delayed to obtain a more accurate display.
@@ -931,25 +1220,35 @@ func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag Upload
 	}
 	if err = gnfdClient.PutObject(c, bucketName, objectName,
 		objectSize, progressReader, opt); err != nil {
-		return toCmdErr(err)
+		return txnHash, err
 	}
 
-	return nil
+	return txnHash, nil
 }
 
 // getObject download the object payload from sp
 func getObject(ctx *cli.Context) error {
 	var err error
 	if ctx.NArg() < 1 {
-		return toCmdErr(fmt.Errorf("args number less than one"))
+		return toCmdErr(invalidArgsError("args number less than one"))
 	}
 
 	urlInfo := ctx.Args().Get(0)
-	bucketName, objectName, err := ParseBucketAndObject(urlInfo)
+	var bucketName, objectName string
+	if ctx.Bool(recursiveFlag) {
+		// the whole bucket can be downloaded in a recursive way
+		bucketName, objectName, err = ParseBucketAndPrefix(urlInfo)
+	} else {
+		bucketName, objectName, err = ParseBucketAndObject(urlInfo)
+	}
 	if err != nil {
 		return toCmdErr(err)
 	}
 
+	if err = checkResumableDownloadLimit(ctx.Bool(resumableFlag)); err != nil {
+		return toCmdErr(err)
+	}
+
 	spEndpoint := ctx.String(spEndpointFlag)
 
 	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false, ForceToUseSpecifiedSpEndpointForDownloadOnly: spEndpoint})
@@ -960,10 +1259,17 @@ func getObject(ctx *cli.Context) error {
 	c, cancelGetObject := context.WithCancel(globalContext)
 	defer cancelGetObject()
 
+	if ctx.Bool(recursiveFlag) {
+		return downloadFolder(ctx, c, gnfdClient, bucketName, objectName)
+	}
+
+	// the payload is written to stdout if the file path is "-"
+	toStdout := ctx.Args().Len() == 2 && ctx.Args().Get(1) == stdioPath
+
 	var filePath string
 	if ctx.Args().Len() == 1 {
 		filePath = objectName
-	} else if ctx.Args().Len() == 2 {
+	} else if ctx.Args().Len() == 2 && !toStdout {
 		filePath = ctx.Args().Get(1)
 		stat, err := os.Stat(filePath)
 		if err == nil {
@@ -977,9 +1283,11 @@ func getObject(ctx *cli.Context) error {
 		}
 	}
 
-	filePath, err = checkIfDownloadFileExist(filePath, objectName)
-	if err != nil {
-		return toCmdErr(err)
+	if !toStdout {
+		filePath, err = checkIfDownloadFileExist(filePath, objectName)
+		if err != nil {
+			return toCmdErr(err)
+		}
 	}
 
 	opt := sdktypes.GetObjectOptions{}
@@ -995,6 +1303,18 @@ func getObject(ctx *cli.Context) error {
 		}
 	}
 
+	if toStdout {
+		if resumableDownload {
+			return toCmdErr(invalidArgsError("resumable download is not supported when writing to stdout"))
+		}
+		size, err := downloadObjectToWriter(c, gnfdClient, "", bucketName, objectName, os.Stdout, opt)
+		if err != nil {
+			return toCmdErr(err)
+		}
+		fmt.Fprintf(os.Stderr, "\ndownload object %s to stdout, content length:%d \n", objectName, uint64(size))
+		return nil
+	}
+
 	if resumableDownload {
 		opt.PartSize = partSize
 		err = gnfdClient.FGetObjectResumable(c, bucketName, objectName, filePath, opt)
@@ -1003,55 +1323,234 @@ func getObject(ctx *cli.Context) error {
 		}
 		fmt.Printf("resumable download object %s, the file path is %s \n", objectName, filePath)
 	} else {
-		var fd *os.File
-		dir := filepath.Dir(filePath)
-		fileName := "." + filepath.Base(filePath) + ".tmp"
-		tempFilePath := filepath.Join(dir, fileName)
-
-		tempFilePath, err = checkIfDownloadFileExist(tempFilePath, objectName)
+		size, err := downloadObject(c, gnfdClient, "", bucketName, objectName, filePath, opt)
 		if err != nil {
 			return toCmdErr(err)
 		}
-		// download to the temp file firstly
-		fd, err = os.OpenFile(tempFilePath, os.O_CREATE|os.O_WRONLY, 0o660)
-		if err != nil {
-			return err
-		}
+		fmt.Printf("\ndownload object %s, the file path is %s, content length:%d \n", objectName, filePath, uint64(size))
+	}
 
-		defer fd.Close()
+	// the checksum can only be verified when the whole payload is downloaded
+	if ctx.Bool(skipVerifyFlag) || startOffset != 0 || endOffset != 0 {
+		return nil
+	}
+	objectDetail, err := gnfdClient.HeadObject(c, bucketName, objectName)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if err = verifyDownloadedFile(gnfdClient, filePath, objectDetail.ObjectInfo); err != nil {
+		return err
+	}
+	fmt.Printf("the checksum of %s is verified \n", filePath)
+	return nil
+}
 
-		body, info, downloadErr := gnfdClient.GetObject(c, bucketName, objectName, opt)
-		if downloadErr != nil {
-			return toCmdErr(downloadErr)
-		}
+// downloadFolder download the objects under the prefix in a recursive way, the folder objects are created as local directories.
+// The download runs as a task so that it can be retried after being interrupted
+func downloadFolder(ctx *cli.Context, c context.Context, gnfdClient client.IClient, bucketName, prefixName string) error {
+	if ctx.Int64(startOffsetFlag) != 0 || ctx.Int64(endOffsetFlag) != 0 {
+		return toCmdErr(invalidArgsError("the range of download body is not supported with recursive flag"))
+	}
 
-		pw := &ProgressWriter{
-			Writer:      fd,
-			Total:       info.Size,
-			StartTime:   time.Now(),
-			LastPrinted: time.Now(),
-		}
+	if prefixName != "" && !strings.HasSuffix(prefixName, "/") {
+		prefixName += "/"
+	}
 
-		_, err = io.Copy(pw, body)
-		if err != nil {
-			return toCmdErr(err)
+	folderPath := "."
+	if ctx.Args().Len() == 2 {
+		folderPath = ctx.Args().Get(1)
+	}
+	// the absolute path is recorded so that the task can be retried in another working directory
+	folderPath, err := filepath.Abs(folderPath)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if err = os.MkdirAll(folderPath, 0o700); err != nil {
+		return toCmdErr(err)
+	}
+
+	filter, err := newObjectFilter(ctx, "")
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	taskState := &TaskState{
+		Lock:        new(sync.Mutex),
+		ObjectState: make(map[int]*UploadTaskObject),
+		TaskID:      uuid.New().String(),
+		Kind:        TaskKindDownload,
+		FolderName:  folderPath,
+		Prefix:      prefixName,
+		BucketName:  bucketName,
+		Status:      TaskStatusCreate,
+		CreateTime:  time.Now().Unix(),
+		DownloadFlag: DownloadFlag{
+			PartSize:   ctx.Uint64(partSizeFlag),
+			Resumable:  ctx.Bool(resumableFlag),
+			SkipVerify: ctx.Bool(skipVerifyFlag),
+		},
+	}
+
+	err = walkObjectsByPage(gnfdClient, c, bucketName, prefixName, true, func(listResult sdktypes.ListObjectsResult) error {
+		for _, object := range listResult.Objects {
+			info := object.ObjectInfo
+			relPath := strings.TrimPrefix(info.ObjectName, prefixName)
+			if object.Removed || relPath == "" || !filter.match(relPath, strings.HasSuffix(relPath, "/")) {
+				continue
+			}
+
+			utj := &UploadTaskObject{
+				BucketName:         bucketName,
+				ObjectName:         info.ObjectName,
+				FilePath:           filepath.Join(folderPath, filepath.FromSlash(relPath)),
+				UploadSingleFolder: strings.HasSuffix(info.ObjectName, "/"),
+				ObjectSize:         int64(info.PayloadSize),
+				Status:             TaskObjectStatusWaitForDownload,
+			}
+			if !utj.UploadSingleFolder && fileExists(utj.FilePath) {
+				utj.Status, utj.Comment = TaskObjectStatusSkipped, "file already exists"
+			}
+			taskState.ObjectState[len(taskState.ObjectState)] = utj
 		}
+		return nil
+	})
+	if err != nil {
+		return toCmdErr(err)
+	}
 
-		err = os.Rename(tempFilePath, filePath)
-		if err != nil {
-			fmt.Printf("failed to rename %s to %s \n", tempFilePath, filePath)
-			return nil
+	printTaskSubmitted(TaskKindDownload, taskState.TaskID)
+	if err = saveTaskState(homeDir, taskState); err != nil {
+		return err
+	}
+	return downloadFolderByTask(ctx, homeDir, gnfdClient, taskState)
+}
+
+// downloadFolderByTask download the unfinished objects of the download task
+func downloadFolderByTask(ctx *cli.Context, homeDir string, gnfdClient client.IClient, taskState *TaskState) error {
+	c, cancelDownload := context.WithCancel(globalContext)
+	defer cancelDownload()
+
+	return runObjectTask(ctx, homeDir, taskState, ctx.Int(parallelFlag), func(object *UploadTaskObject) (string, error) {
+		return downloadTaskObject(c, gnfdClient, taskState.TaskID, object, taskState.DownloadFlag)
+	})
+}
+
+// downloadTaskObject download an object of the download task and verify the downloaded file
+func downloadTaskObject(c context.Context, gnfdClient client.IClient, taskID string, object *UploadTaskObject,
+	downloadFlag DownloadFlag,
+) (string, error) {
+	if object.UploadSingleFolder {
+		return TaskObjectStatusDownloaded, os.MkdirAll(object.FilePath, 0o700)
+	}
+
+	objectDetail, err := gnfdClient.HeadObject(c, object.BucketName, object.ObjectName)
+	if err != nil {
+		return "", err
+	}
+	if objectDetail.ObjectInfo.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+		return "", ErrObjectSeal
+	}
+	if err = os.MkdirAll(filepath.Dir(object.FilePath), 0o700); err != nil {
+		return "", err
+	}
+
+	if downloadFlag.Resumable {
+		err = gnfdClient.FGetObjectResumable(c, object.BucketName, object.ObjectName, object.FilePath, sdktypes.GetObjectOptions{PartSize: downloadFlag.PartSize})
+	} else {
+		_, err = downloadObject(c, gnfdClient, taskID, object.BucketName, object.ObjectName, object.FilePath, sdktypes.GetObjectOptions{})
+	}
+	if err == nil && !downloadFlag.SkipVerify {
+		err = verifyDownloadedFile(gnfdClient, object.FilePath, objectDetail.ObjectInfo)
+	}
+	if err != nil {
+		return "", err
+	}
+	return TaskObjectStatusDownloaded, nil
+}
+
+// downloadObject download the object payload to a temp file firstly and rename it to the file path after finishing,
+// it returns the content length of the downloaded payload. The task id is empty if the object is not downloaded in a task
+func downloadObject(c context.Context, gnfdClient client.IClient, taskID, bucketName, objectName, filePath string,
+	opt sdktypes.GetObjectOptions,
+) (int64, error) {
+	return downloadToFile(filePath, func(writer io.Writer) (int64, error) {
+		return downloadObjectToWriter(c, gnfdClient, taskID, bucketName, objectName, writer, opt)
+	})
+}
+
+// downloadToFile write the payload to the temp file of the file path and rename it after the download finishes.
+// The temp file left by an interrupted download is truncated, and the temp file is removed if the download fails,
+// so that the download can be retried
+func downloadToFile(filePath string, download func(writer io.Writer) (int64, error)) (size int64, err error) {
+	tempFilePath := filepath.Join(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	fd, err := os.OpenFile(tempFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o660)
+	if err != nil {
+		return 0, err
+	}
+	renamed := false
+	defer func() {
+		// the file is closed again if the download fails, the error of closing twice is ignored
+		_ = fd.Close()
+		if !renamed {
+			_ = os.Remove(tempFilePath)
 		}
-		fmt.Printf("\ndownload object %s, the file path is %s, content length:%d \n", objectName, filePath, uint64(info.Size))
+	}()
+
+	size, err = download(fd)
+	if err != nil {
+		return 0, err
+	}
+	// the temp file should be closed before being renamed on windows
+	if err = fd.Close(); err != nil {
+		return 0, err
+	}
+	if err = os.Rename(tempFilePath, filePath); err != nil {
+		return 0, fmt.Errorf("failed to rename %s to %s", tempFilePath, filePath)
 	}
+	renamed = true
+	return size, nil
+}
 
-	return nil
+// downloadObjectToWriter copy the object payload to the writer, the downloading progress is printed to stderr
+// if the writer is stdout so that the payload can be piped to other commands
+func downloadObjectToWriter(c context.Context, gnfdClient client.IClient, taskID, bucketName, objectName string, writer io.Writer,
+	opt sdktypes.GetObjectOptions,
+) (int64, error) {
+	body, info, err := gnfdClient.GetObject(c, bucketName, objectName, opt)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	pw := &ProgressWriter{
+		Writer:      writer,
+		Name:        objectName,
+		TaskID:      taskID,
+		Bucket:      bucketName,
+		Total:       info.Size,
+		StartTime:   time.Now(),
+		LastPrinted: time.Now(),
+	}
+	if writer == os.Stdout {
+		pw.Out = os.Stderr
+	}
+
+	if _, err = io.Copy(pw, body); err != nil {
+		emitObjectEvent(progressEventFailed, taskID, bucketName, objectName, err)
+		return 0, err
+	}
+	return info.Size, nil
 }
 
 // cancelCreateObject cancel the created object on chain
 func cancelCreateObject(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be one"))
+		return toCmdErr(invalidArgsError("args number should be one"))
 	}
 
 	urlInfo := ctx.Args().Get(0)
@@ -1084,7 +1583,7 @@ func cancelCreateObject(ctx *cli.Context) error {
 
 func listObjects(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be one"))
+		return toCmdErr(invalidArgsError("args number should be one"))
 	}
 
 	bucketName, prefixName, err := ParseBucketAndPrefix(ctx.Args().Get(0))
@@ -1104,8 +1603,19 @@ func listObjects(ctx *cli.Context) error {
 		return toCmdErr(ErrBucketNotExist)
 	}
 
-	supportRecursive := ctx.Bool(recursiveFlag)
-	err = listObjectByPage(client, c, bucketName, prefixName, supportRecursive)
+	filter, err := newObjectFilter(ctx, "")
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	option := objectListOption{
+		Recursive:     ctx.Bool(recursiveFlag),
+		Long:          ctx.Bool(longFlag),
+		HumanReadable: ctx.Bool(humanReadableFlag),
+		ShowRemoved:   ctx.Bool(showRemovedFlag),
+		SortBy:        fmt.Sprintf("%s", ctx.Generic(sortByFlag)),
+	}
+	err = listObjectByPage(client, c, bucketName, prefixName, option, filter)
 	if err != nil {
 		return toCmdErr(err)
 	}
@@ -1113,61 +1623,230 @@ func listObjects(ctx *cli.Context) error {
 	return nil
 }
 
-func listObjectByPage(cli client.IClient, c context.Context, bucketName, prefixName string, isRecursive bool) error {
-	var (
-		listResult        sdktypes.ListObjectsResult
-		continuationToken string
-		err               error
-	)
+// objectListOption is the options of listing the objects by "object ls"
+type objectListOption struct {
+	Recursive     bool
+	Long          bool
+	HumanReadable bool
+	ShowRemoved   bool
+	// SortBy is empty if the objects are shown in the order of listing
+	SortBy string
+}
 
-	for {
-		if isRecursive {
-			listResult, err = cli.ListObjects(c, bucketName, sdktypes.ListObjectsOptions{
-				ShowRemovedObject: false,
-				MaxKeys:           defaultMaxKey,
-				ContinuationToken: continuationToken,
-				Prefix:            prefixName,
-			})
-		} else {
-			listResult, err = cli.ListObjects(c, bucketName, sdktypes.ListObjectsOptions{
-				ShowRemovedObject: false,
-				Delimiter:         "/",
-				MaxKeys:           defaultMaxKey,
-				ContinuationToken: continuationToken,
-				Prefix:            prefixName,
-			})
+func listObjectByPage(cli client.IClient, c context.Context, bucketName, prefixName string, option objectListOption, filter *objectFilter) error {
+	// the structured output and the sorted objects are printed as a whole after all the pages are listed
+	collect := isStructuredOutput() || option.SortBy != ""
+	var listed sdktypes.ListObjectsResult
+	listOptions := sdktypes.ListObjectsOptions{
+		ShowRemovedObject: option.ShowRemoved,
+		MaxKeys:           defaultMaxKey,
+		Prefix:            prefixName,
+	}
+	if !option.Recursive {
+		listOptions.Delimiter = "/"
+	}
+	if option.Long && !isStructuredOutput() {
+		printLongListHeader()
+	}
+	err := walkObjects(cli, c, bucketName, listOptions, func(listResult sdktypes.ListObjectsResult) error {
+		listResult = filterListResult(listResult, prefixName, filter)
+		if !collect {
+			printListResult(listResult, option)
+			return nil
 		}
+		listed.Objects = append(listed.Objects, listResult.Objects...)
+		listed.CommonPrefixes = append(listed.CommonPrefixes, listResult.CommonPrefixes...)
+		return nil
+	})
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if !collect {
+		return nil
+	}
+
+	sortObjects(listed.Objects, option.SortBy)
+	sort.Strings(listed.CommonPrefixes)
+	if !isStructuredOutput() {
+		printListResult(listed, option)
+		return nil
+	}
+	output := objectListOutput{Objects: make([]objectOutput, 0, len(listed.Objects)), Prefixes: make([]string, 0, len(listed.CommonPrefixes))}
+	for _, object := range listed.Objects {
+		output.Objects = append(output.Objects, toObjectOutput(object))
+	}
+	output.Prefixes = append(output.Prefixes, listed.CommonPrefixes...)
+	return printOutput(output)
+}
+
+// toObjectOutput convert the object of the list result to the structured output
+func toObjectOutput(object *sdktypes.ObjectMeta) objectOutput {
+	info := object.ObjectInfo
+	item := objectOutput{
+		ObjectName:  info.ObjectName,
+		ObjectID:    info.Id.String(),
+		Owner:       info.Owner,
+		Size:        info.PayloadSize,
+		ContentType: info.ContentType,
+		Visibility:  info.Visibility.String(),
+		Status:      info.ObjectStatus.String(),
+		CreateTime:  formatOutputTime(info.CreateAt),
+		Checksums:   make([]string, 0, len(info.Checksums)),
+		Tags:        make(map[string]string),
+		Removed:     object.Removed,
+	}
+	for _, checksum := range info.Checksums {
+		item.Checksums = append(item.Checksums, hex.EncodeToString(checksum))
+	}
+	if info.Tags != nil {
+		for _, tag := range info.Tags.Tags {
+			item.Tags[tag.Key] = tag.Value
+		}
+	}
+	return item
+}
+
+// walkObjectsByPage list the objects under the prefix page by page and pass every page to the handler
+func walkObjectsByPage(cli client.IClient, c context.Context, bucketName, prefixName string, isRecursive bool,
+	handler func(listResult sdktypes.ListObjectsResult) error,
+) error {
+	listOptions := sdktypes.ListObjectsOptions{
+		ShowRemovedObject: false,
+		MaxKeys:           defaultMaxKey,
+		Prefix:            prefixName,
+	}
+	if !isRecursive {
+		listOptions.Delimiter = "/"
+	}
+	return walkObjects(cli, c, bucketName, listOptions, handler)
+}
+
+// walkObjects list the objects by the options page by page and pass every page to the handler
+func walkObjects(cli client.IClient, c context.Context, bucketName string, listOptions sdktypes.ListObjectsOptions,
+	handler func(listResult sdktypes.ListObjectsResult) error,
+) error {
+	for {
+		listResult, err := cli.ListObjects(c, bucketName, listOptions)
 		if err != nil {
-			return toCmdErr(err)
+			return err
 		}
 
-		printListResult(listResult)
+		if err = handler(listResult); err != nil {
+			return err
+		}
 		if !listResult.IsTruncated {
 			break
 		}
 
-		continuationToken = listResult.NextContinuationToken
+		listOptions.ContinuationToken = listResult.NextContinuationToken
 	}
 	return nil
 }
 
-func printListResult(listResult sdktypes.ListObjectsResult) {
+// sortObjects sort the objects by the name, by the size from the largest or by the create time from the newest,
+// the objects are sorted by the name if the sizes or the times are the same
+func sortObjects(objects []*sdktypes.ObjectMeta, sortBy string) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		a, b := objects[i].ObjectInfo, objects[j].ObjectInfo
+		switch {
+		case sortBy == objectSortBySize && a.PayloadSize != b.PayloadSize:
+			return a.PayloadSize > b.PayloadSize
+		case sortBy == objectSortByTime && a.CreateAt != b.CreateAt:
+			return a.CreateAt > b.CreateAt
+		case sortBy == "":
+			return false
+		default:
+			return a.ObjectName < b.ObjectName
+		}
+	})
+}
+
+// filterListResult keep the objects and the folders of the list result which are selected by the filter
+func filterListResult(listResult sdktypes.ListObjectsResult, prefixName string, filter *objectFilter) sdktypes.ListObjectsResult {
+	if filter.isEmpty() {
+		return listResult
+	}
+	objects := make([]*sdktypes.ObjectMeta, 0, len(listResult.Objects))
+	for _, object := range listResult.Objects {
+		objectName := object.ObjectInfo.ObjectName
+		if filter.match(relativeObjectName(prefixName, objectName), strings.HasSuffix(objectName, "/")) {
+			objects = append(objects, object)
+		}
+	}
+	prefixes := make([]string, 0, len(listResult.CommonPrefixes))
+	for _, prefix := range listResult.CommonPrefixes {
+		if filter.match(relativeObjectName(prefixName, prefix), true) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	listResult.Objects = objects
+	listResult.CommonPrefixes = prefixes
+	return listResult
+}
+
+func printListResult(listResult sdktypes.ListObjectsResult, option objectListOption) {
 	for _, object := range listResult.Objects {
 		info := object.ObjectInfo
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(info.CreateAt, 0).In(location)
+		size := strconv.FormatUint(info.PayloadSize, 10)
+		if option.HumanReadable {
+			size = getConvertSize(int64(info.PayloadSize))
+		}
+		if !option.Long {
+			fmt.Printf("%-*s %15s %s \n", timeColumnWidth(), formatUnixTime(info.CreateAt), size, info.ObjectName)
+			continue
+		}
 
-		fmt.Printf("%s %15d %s \n", t.Format(iso8601DateFormat), info.PayloadSize, info.ObjectName)
+		status := strings.ToLower(strings.TrimPrefix(info.ObjectStatus.String(), "OBJECT_STATUS_"))
+		if object.Removed {
+			status = "removed"
+		}
+		checksum := "-"
+		if len(info.Checksums) > 0 {
+			checksum = hex.EncodeToString(info.Checksums[0])
+			if len(checksum) > 8 {
+				checksum = checksum[:8]
+			}
+		}
+		fmt.Printf(longListFormat(), formatUnixTime(info.CreateAt), size, getVisibilityName(info.Visibility), status,
+			orDash(info.ContentType), info.Owner, info.Id.String(), checksum, info.ObjectName, formatTags(info.Tags))
 	}
 	// list the folders
 	for _, prefix := range listResult.CommonPrefixes {
-		fmt.Printf("%s %15s %s \n", strings.Repeat(" ", len(iso8601DateFormat)), "PRE", prefix)
+		fmt.Printf("%s %15s %s \n", strings.Repeat(" ", timeColumnWidth()), "PRE", prefix)
+	}
+}
+
+func printLongListHeader() {
+	fmt.Printf(longListFormat(), "create-time", "size", "visibility", "status", "content-type", "owner", "object-id", "checksum", "name", "tags")
+}
+
+// longListFormat return the format of the lines of the long listing
+func longListFormat() string {
+	return fmt.Sprintf("%%-%ds %%15s %%-11s %%-12s %%-24s %%-%ds %%-10s %%-8s %%s  %%s\n", timeColumnWidth(), operatorAddressLen)
+}
+
+// formatTags format the tags as key=value pairs separated by commas, or "-" if there is no tag
+func formatTags(tags *storageTypes.ResourceTags) string {
+	if tags == nil || len(tags.Tags) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(tags.Tags))
+	for _, tag := range tags.Tags {
+		pairs = append(pairs, tag.Key+"="+tag.Value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
 	}
+	return value
 }
 
 func updateObject(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be one"))
+		return toCmdErr(invalidArgsError("args number should be one"))
 	}
 
 	urlInfo := ctx.Args().First()
@@ -1186,18 +1865,17 @@ func updateObject(ctx *cli.Context) error {
 
 	visibility := ctx.Generic(visibilityFlag)
 	if visibility == "" {
-		return toCmdErr(fmt.Errorf("visibity must set to be updated"))
+		return toCmdErr(invalidArgsError("visibity must set to be updated"))
 	}
 
 	visibilityType, typeErr := getVisibilityType(fmt.Sprintf("%s", visibility))
 	if typeErr != nil {
-		return typeErr
+		return toCmdErr(invalidArgsError("%v", typeErr))
 	}
 
 	txnHash, err := client.UpdateObjectVisibility(c, bucketName, objectName, visibilityType, sdktypes.UpdateObjectOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
-		fmt.Println("update object visibility error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	err = waitTxnStatus(client, c, txnHash, "UpdateObject")
@@ -1207,8 +1885,7 @@ func updateObject(ctx *cli.Context) error {
 
 	objectDetail, err := client.HeadObject(c, bucketName, objectName)
 	if err != nil {
-		// head fail, no need to print the error
-		return nil
+		return toCmdErr(err)
 	}
 
 	fmt.Printf("update object visibility finished, latest object visibility:%s\n", objectDetail.ObjectInfo.GetVisibility().String())
@@ -1218,7 +1895,7 @@ func updateObject(ctx *cli.Context) error {
 
 func getUploadInfo(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be 1"))
+		return toCmdErr(invalidArgsError("args number should be 1"))
 	}
 
 	urlInfo := ctx.Args().Get(0)
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_downloadToFile(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "a.txt")
+	tempFilePath := filepath.Join(dir, ".a.txt.tmp")
+	payload := "the whole payload"
+
+	// the failed download should not leave the temp file
+	_, err := downloadToFile(filePath, func(writer io.Writer) (int64, error) {
+		_, _ = io.WriteString(writer, "the whole")
+		return 0, errors.New("connection reset")
+	})
+	if err == nil {
+		t.Fatalf("downloadToFile() error = nil, want the download error")
+	}
+	if fileExists(tempFilePath) || fileExists(filePath) {
+		t.Fatalf("downloadToFile() left the files after the download failed")
+	}
+
+	// the partial temp file left by an interrupted process should be overwritten when retrying
+	if err = os.WriteFile(tempFilePath, []byte("the partial payload which is longer than the whole one"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	size, err := downloadToFile(filePath, func(writer io.Writer) (int64, error) {
+		n, err := io.WriteString(writer, payload)
+		return int64(n), err
+	})
+	if err != nil {
+		t.Fatalf("downloadToFile() error = %v", err)
+	}
+	if size != int64(len(payload)) {
+		t.Errorf("downloadToFile() size = %d, want %d", size, len(payload))
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != payload {
+		t.Errorf("downloaded content = %q, want %q", content, payload)
+	}
+	if fileExists(tempFilePath) {
+		t.Errorf("downloadToFile() left the temp file after the download finished")
+	}
+}
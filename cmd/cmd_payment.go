@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/urfave/cli/v2"
@@ -69,13 +68,12 @@ func buyQuotaForBucket(ctx *cli.Context) error {
 
 	targetQuota := ctx.Uint64(chargeQuotaFlag)
 	if targetQuota == 0 {
-		return toCmdErr(errors.New("target quota not set"))
+		return toCmdErr(invalidArgsError("target quota not set"))
 	}
 
 	txnHash, err := client.BuyQuotaForBucket(c, bucketName, targetQuota, sdktypes.BuyQuotaOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
-		fmt.Println("buy quota error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	fmt.Printf("buy quota for bucket: %s \n", bucketName)
@@ -94,7 +94,7 @@ func Deposit(ctx *cli.Context) error {
 	amountStr := ctx.String(amountFlag)
 	amount, ok := math.NewIntFromString(amountStr)
 	if !ok {
-		return toCmdErr(fmt.Errorf("invalid amount %s", amountStr))
+		return toCmdErr(invalidArgsError("invalid amount %s", amountStr))
 	}
 	c, deposit := context.WithCancel(globalContext)
 	defer deposit()
@@ -154,7 +154,7 @@ func Withdraw(ctx *cli.Context) error {
 	amountStr := ctx.String(amountFlag)
 	amount, ok := math.NewIntFromString(amountStr)
 	if !ok {
-		return toCmdErr(fmt.Errorf("invalid amount %s", amountStr))
+		return toCmdErr(invalidArgsError("invalid amount %s", amountStr))
 	}
 	c, deposit := context.WithCancel(globalContext)
 	defer deposit()
@@ -220,14 +220,17 @@ func listPaymentAccounts(ctx *cli.Context) error {
 		ownerAddr = acct.GetAddress().String()
 	}
 	accounts, err := client.GetPaymentAccountsByOwner(c, ownerAddr)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			fmt.Println("Accounts not exist")
-			return nil
-		}
+	if err != nil && !strings.Contains(err.Error(), "not found") {
 		return toCmdErr(err)
 	}
-	if len(accounts) == 0 {
+	if isStructuredOutput() {
+		output := make([]paymentAccountOutput, 0, len(accounts))
+		for _, account := range accounts {
+			output = append(output, paymentAccountOutput{Address: account.Addr, Owner: account.Owner, Refundable: account.Refundable})
+		}
+		return printOutput(output)
+	}
+	if err != nil || len(accounts) == 0 {
 		fmt.Println("Accounts not exist")
 		return nil
 	}
@@ -146,7 +146,7 @@ $ mechain-cmd policy ls --groupId 111  grn:o::mechain-bucket/mechain-object`,
 
 func putPolicy(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be one"))
+		return toCmdErr(invalidArgsError("args number should be one"))
 	}
 
 	var resourceType ResourceType
@@ -203,7 +203,7 @@ func putPolicy(ctx *cli.Context) error {
 
 func deletePolicy(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be one"))
+		return toCmdErr(invalidArgsError("args number should be one"))
 	}
 
 	resource := ctx.Args().Get(0)
@@ -216,7 +216,7 @@ func deletePolicy(ctx *cli.Context) error {
 
 func listPolicy(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
-		return toCmdErr(fmt.Errorf("args number should be one"))
+		return toCmdErr(invalidArgsError("args number should be one"))
 	}
 
 	resource := ctx.Args().Get(0)
@@ -372,7 +372,9 @@ func handleListPolicy(ctx *cli.Context, resource string, policyType ResourceType
 			return toCmdErr(err)
 		}
 
-		listPolicyInfo(0, grantee, resource, *policyInfo)
+		if err = listPolicyInfo(0, grantee, resource, *policyInfo); err != nil {
+			return toCmdErr(err)
+		}
 	}
 
 	return nil
@@ -571,8 +573,7 @@ func listObjectPolicy(ctx *cli.Context, cli client.IClient, bucketName, objectNa
 		return err
 	}
 
-	listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
-	return nil
+	return listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
 }
 
 func printBucketPolicy(ctx *cli.Context, cli client.IClient, bucketName string) {
@@ -612,8 +613,7 @@ func listBucketPolicy(ctx *cli.Context, cli client.IClient, bucketName, resource
 		return err
 	}
 
-	listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
-	return nil
+	return listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
 }
 
 func parseResourceType(resource string) (ResourceType, error) {
@@ -625,7 +625,7 @@ func parseResourceType(resource string) (ResourceType, error) {
 	} else if strings.HasPrefix(resource, GroupResourcePrefix) {
 		resourceType = GroupResourceType
 	} else {
-		return -1, toCmdErr(errors.New("invalid resource name"))
+		return -1, toCmdErr(invalidArgsError("invalid resource name"))
 	}
 	return resourceType, nil
 }
@@ -642,7 +642,28 @@ func getActionStr(actions []permTypes.ActionType) string {
 	return action
 }
 
-func listPolicyInfo(groupId uint64, grantee, resourceName string, policyInfo permTypes.Policy) {
+func listPolicyInfo(groupId uint64, grantee, resourceName string, policyInfo permTypes.Policy) error {
+	principal := grantee
+	if groupId > 0 {
+		principal = "groupID-" + strconv.FormatUint(groupId, 10)
+	}
+	if isStructuredOutput() {
+		output := make([]policyStatementOutput, 0, len(policyInfo.Statements))
+		for _, statement := range policyInfo.Statements {
+			actions := make([]string, 0, len(statement.GetActions()))
+			for _, action := range statement.GetActions() {
+				actions = append(actions, action.String())
+			}
+			output = append(output, policyStatementOutput{
+				Principal: principal,
+				Actions:   actions,
+				Effect:    statement.GetEffect().String(),
+				Resource:  resourceName,
+			})
+		}
+		return printOutput(output)
+	}
+
 	var format string
 	if groupId > 0 {
 		format = fmt.Sprintf("%%-%ds %%-%ds %%-%ds %%-%ds  \n", 15, 40, 10, 20)
@@ -654,10 +675,7 @@ func listPolicyInfo(groupId uint64, grantee, resourceName string, policyInfo per
 	for _, statement := range policyInfo.Statements {
 		actionName := getActionStr(statement.GetActions())
 		effectName := statement.GetEffect().String()[len("EFFECT_"):]
-		if groupId > 0 {
-			fmt.Printf(format, "groupID-"+strconv.FormatUint(groupId, 10), actionName, effectName, resourceName)
-		} else {
-			fmt.Printf(format, ""+grantee, actionName, effectName, resourceName)
-		}
+		fmt.Printf(format, principal, actionName, effectName, resourceName)
 	}
+	return nil
 }
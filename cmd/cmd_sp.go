@@ -66,8 +66,20 @@ func ListSP(ctx *cli.Context) error {
 
 	spInfo, err := client.ListStorageProviders(c, false)
 	if err != nil {
-		fmt.Println("fail to list SP:", err.Error())
-		return nil
+		return toCmdErr(err)
+	}
+
+	if isStructuredOutput() {
+		output := make([]spOutput, 0, len(spInfo))
+		for _, info := range spInfo {
+			output = append(output, spOutput{
+				Name:            info.Description.GetMoniker(),
+				OperatorAddress: info.OperatorAddress,
+				Endpoint:        info.Endpoint,
+				Status:          info.Status.String(),
+			})
+		}
+		return printOutput(output)
 	}
 
 	if len(spInfo) == 0 {
@@ -154,14 +166,12 @@ func getQuotaPrice(ctx *cli.Context) error {
 
 	quotaPrice, err := price.ReadPrice.Float64()
 	if err != nil {
-		fmt.Println("get quota price error:", err.Error())
-		return err
+		return toCmdErr(err)
 	}
 
 	storagePrice, err := price.StorePrice.Float64()
 	if err != nil {
-		fmt.Println("get storage price error:", err.Error())
-		return err
+		return toCmdErr(err)
 	}
 
 	fmt.Println("get bucket read quota price:", quotaPrice, " wei/byte")
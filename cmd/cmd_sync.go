@@ -0,0 +1,546 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
+	"github.com/zkMeLabs/mechain-go-sdk/client"
+	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
+)
+
+// errSyncObjectLost indicates the remote object was removed for the update, but the new content failed to upload
+var errSyncObjectLost = errors.New("the remote object was removed but the new content failed to upload, the object is lost")
+
+const (
+	syncActionUpload   = "upload"
+	syncActionUpdate   = "update"
+	syncActionDownload = "download"
+	syncActionDelete   = "delete"
+	syncActionSkip     = "skip"
+)
+
+// syncItem indicates the action which the sync command takes on an object and its local file
+type syncItem struct {
+	Action     string
+	ObjectName string
+	FilePath   string
+	Size       int64
+	IsFolder   bool
+	Reason     string
+	objectInfo *storageTypes.ObjectInfo
+}
+
+// cmdSyncObjects return the command to synchronize a local folder and the objects under a prefix in one direction
+func cmdSyncObjects() *cli.Command {
+	return &cli.Command{
+		Name:      "sync",
+		Action:    syncObjects,
+		Usage:     "sync a local folder to a bucket prefix or a bucket prefix to a local folder",
+		ArgsUsage: "SOURCE DESTINATION",
+		Description: `
+Synchronize the files of the source to the destination in one direction, one of them should be a local folder
+and the other an OBJECT-URL of the bucket or prefix.
+A file is skipped when the object has the same payload size and the same integrity hash of the primary SP on chain,
+otherwise it is uploaded or downloaded again. The uploads wait for the objects to be sealed, and the object of the same size
+which is not sealed yet is skipped. If --delete is set, the objects or files which only exist
+in the destination will be removed. The plan of the sync is printed before it runs, and it needs to be confirmed
+if it deletes anything or changes more than 100 files or objects.
+
+Examples:
+# sync the local folder to the prefix of the bucket
+$ mechain-cmd object sync ./data mechain://mechain-bucket/data
+# sync the prefix of the bucket to the local folder and remove the local files which are not in the bucket
+$ mechain-cmd object sync --delete mechain://mechain-bucket/data ./data
+# print the sync plan without running it
+$ mechain-cmd object sync --dryRun ./data mechain://mechain-bucket/data`,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  secondarySPFlag,
+				Value: "",
+				Usage: "indicate the Secondary SP addr string list, input like addr1,addr2,addr3",
+			},
+			&cli.StringFlag{
+				Name:  contentTypeFlag,
+				Value: "",
+				Usage: "indicate object content-type",
+			},
+			&cli.GenericFlag{
+				Name: visibilityFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{publicReadType, privateType, inheritType},
+					Default: inheritType,
+				},
+				Usage: "set visibility of the uploaded objects",
+			},
+			&cli.Uint64Flag{
+				Name: partSizeFlag,
+				// the default part size is 32M
+				Value: 32 * 1024 * 1024,
+				Usage: "indicate the resumable upload 's part size, uploading a large file in multiple parts. " +
+					"The part size is an integer multiple of the segment size.",
+			},
+			&cli.StringFlag{
+				Name:  tagFlag,
+				Value: "",
+				Usage: "set one or more tags of the uploaded objects. The tag value is key-value pairs in json array format. E.g. [{\"key\":\"key1\",\"value\":\"value1\"},{\"key\":\"key2\",\"value\":\"value2\"}]",
+			},
+			&cli.BoolFlag{
+				Name:  deleteFlag,
+				Value: false,
+				Usage: "delete the objects or files which exist in the destination but not in the source",
+			},
+			dryRunCliFlag(),
+			yesCliFlag(),
+		}, filterFlags()...),
+	}
+}
+
+// syncObjects compare the source and the destination, print the plan and run it
+func syncObjects(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return toCmdErr(invalidArgsError("args number should be 2"))
+	}
+
+	source, destination := ctx.Args().Get(0), ctx.Args().Get(1)
+	isUpload := !isObjectUrl(source) && isObjectUrl(destination)
+	isDownload := isObjectUrl(source) && !isObjectUrl(destination)
+	if !isUpload && !isDownload {
+		return toCmdErr(invalidArgsError("one of the source and destination should be a local folder and the other should be an OBJECT-URL"))
+	}
+
+	folderPath, urlInfo := source, destination
+	if isDownload {
+		folderPath, urlInfo = destination, source
+	}
+
+	bucketName, prefixName, err := ParseBucketAndPrefix(urlInfo)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if prefixName != "" && !strings.HasSuffix(prefixName, "/") {
+		prefixName += "/"
+	}
+
+	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	c, cancelSync := context.WithCancel(globalContext)
+	defer cancelSync()
+
+	if _, err = gnfdClient.HeadBucket(c, bucketName); err != nil {
+		return toCmdErr(ErrBucketNotExist)
+	}
+
+	filter, err := newObjectFilter(ctx, filepath.Join(folderPath, defaultIgnoreFileName))
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	remoteObjects, err := listObjectInfos(gnfdClient, c, bucketName, prefixName)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	// the objects which are not selected by the filter are neither synchronized nor deleted
+	for objectName := range remoteObjects {
+		if !filter.match(strings.TrimPrefix(objectName, prefixName), strings.HasSuffix(objectName, "/")) {
+			delete(remoteObjects, objectName)
+		}
+	}
+
+	var plan []*syncItem
+	if isUpload {
+		plan, err = planUploadSync(gnfdClient, folderPath, prefixName, remoteObjects, filter, ctx.Bool(deleteFlag))
+	} else {
+		plan, err = planDownloadSync(gnfdClient, folderPath, prefixName, remoteObjects, filter, ctx.Bool(deleteFlag))
+	}
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	printSyncPlan(plan)
+	if ctx.Bool(dryRunFlag) {
+		return nil
+	}
+
+	var deleteNum, changeNum int
+	for _, item := range plan {
+		if item.Action == syncActionDelete {
+			deleteNum++
+		}
+		if item.Action != syncActionSkip {
+			changeNum++
+		}
+	}
+	if deleteNum > 0 || changeNum > confirmThreshold {
+		confirmed, err := confirmOperation(ctx, fmt.Sprintf("run the sync plan with %d changes and %d deletions", changeNum, deleteNum))
+		if err != nil {
+			return toCmdErr(err)
+		}
+		if !confirmed {
+			fmt.Println("the sync is canceled")
+			return nil
+		}
+	}
+
+	uploadFlag := UploadFlag{}
+	if isUpload {
+		if uploadFlag, err = getUploadFlag(ctx); err != nil {
+			return toCmdErr(err)
+		}
+	}
+
+	var failedNum, lostNum int
+	for _, item := range plan {
+		if item.Action == syncActionSkip {
+			continue
+		}
+		if err = runSyncItem(c, gnfdClient, bucketName, uploadFlag, item); err != nil {
+			failedNum++
+			if errors.Is(err, errSyncObjectLost) {
+				lostNum++
+			}
+			fmt.Printf("\r%s %s failed: %s\n", item.Action, item.ObjectName, err.Error())
+			continue
+		}
+		fmt.Printf("\r%s %s finished\n", item.Action, item.ObjectName)
+	}
+
+	if lostNum > 0 {
+		return toCmdErr(fmt.Errorf("%d of the sync actions failed, %d remote objects were removed for the update "+
+			"but not uploaded again, run the sync again to upload them", failedNum, lostNum))
+	}
+	if failedNum > 0 {
+		return toCmdErr(fmt.Errorf("%d of the sync actions failed", failedNum))
+	}
+	fmt.Println("sync finished")
+	return nil
+}
+
+// planUploadSync decide the action of every local file which needs to be synchronized to the bucket
+func planUploadSync(gnfdClient client.IClient, folderPath, prefixName string,
+	remoteObjects map[string]*storageTypes.ObjectInfo, filter *objectFilter, deleteExtra bool,
+) ([]*syncItem, error) {
+	fileInfo, err := os.Stat(folderPath)
+	if err != nil {
+		return nil, err
+	}
+	if !fileInfo.IsDir() {
+		return nil, errors.New("the local path to sync should be a folder")
+	}
+
+	plan := make([]*syncItem, 0)
+	localObjects := make(map[string]bool)
+	err = filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(folderPath, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		if info.IsDir() && filter.excluded(filepath.ToSlash(relPath), true) {
+			return filepath.SkipDir
+		}
+		if !filter.match(filepath.ToSlash(relPath), info.IsDir()) {
+			return nil
+		}
+
+		item := &syncItem{
+			ObjectName: prefixName + filepath.ToSlash(relPath),
+			FilePath:   path,
+			Size:       info.Size(),
+			IsFolder:   info.IsDir(),
+		}
+		if item.IsFolder {
+			item.ObjectName += "/"
+			item.Size = 0
+		}
+		localObjects[item.ObjectName] = true
+		item.objectInfo = remoteObjects[item.ObjectName]
+
+		changed, reason, err := compareForSync(gnfdClient, item)
+		if err != nil {
+			return err
+		}
+		item.Reason = reason
+		switch {
+		case item.objectInfo == nil:
+			item.Action = syncActionUpload
+		case changed:
+			item.Action = syncActionUpdate
+		default:
+			item.Action = syncActionSkip
+		}
+		plan = append(plan, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if deleteExtra {
+		for _, objectName := range sortedObjectNames(remoteObjects) {
+			if localObjects[objectName] || objectName == prefixName {
+				continue
+			}
+			plan = append(plan, &syncItem{
+				Action:     syncActionDelete,
+				ObjectName: objectName,
+				Size:       int64(remoteObjects[objectName].PayloadSize),
+				Reason:     "not exist in local folder",
+				objectInfo: remoteObjects[objectName],
+			})
+		}
+	}
+	return plan, nil
+}
+
+// planDownloadSync decide the action of every object which needs to be synchronized to the local folder
+func planDownloadSync(gnfdClient client.IClient, folderPath, prefixName string,
+	remoteObjects map[string]*storageTypes.ObjectInfo, filter *objectFilter, deleteExtra bool,
+) ([]*syncItem, error) {
+	plan := make([]*syncItem, 0)
+	for _, objectName := range sortedObjectNames(remoteObjects) {
+		relPath := strings.TrimPrefix(objectName, prefixName)
+		if relPath == "" {
+			continue
+		}
+		objectInfo := remoteObjects[objectName]
+		item := &syncItem{
+			ObjectName: objectName,
+			FilePath:   filepath.Join(folderPath, filepath.FromSlash(relPath)),
+			Size:       int64(objectInfo.PayloadSize),
+			IsFolder:   strings.HasSuffix(objectName, "/"),
+			objectInfo: objectInfo,
+		}
+
+		if !item.IsFolder && objectInfo.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+			item.Action, item.Reason = syncActionSkip, "object not sealed"
+			plan = append(plan, item)
+			continue
+		}
+
+		stat, err := os.Stat(item.FilePath)
+		switch {
+		case os.IsNotExist(err):
+			item.Action, item.Reason = syncActionDownload, "not exist in local folder"
+		case err != nil:
+			return nil, err
+		case stat.IsDir() != item.IsFolder:
+			return nil, fmt.Errorf("the local path %s conflicts with the object %s", item.FilePath, objectName)
+		default:
+			changed, reason, err := compareForSync(gnfdClient, item)
+			if err != nil {
+				return nil, err
+			}
+			item.Action, item.Reason = syncActionSkip, reason
+			if changed {
+				item.Action = syncActionDownload
+			}
+		}
+		plan = append(plan, item)
+	}
+
+	if !deleteExtra {
+		return plan, nil
+	}
+
+	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(folderPath, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if relPath != "." && filter.excluded(filepath.ToSlash(relPath), true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !filter.match(filepath.ToSlash(relPath), false) {
+			return nil
+		}
+		objectName := prefixName + filepath.ToSlash(relPath)
+		if _, ok := remoteObjects[objectName]; ok {
+			return nil
+		}
+		plan = append(plan, &syncItem{
+			Action:     syncActionDelete,
+			ObjectName: objectName,
+			FilePath:   path,
+			Size:       info.Size(),
+			Reason:     "not exist in bucket",
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// compareForSync check whether the local file differs from the object, the integrity hash of the primary SP
+// is only computed when the payload size is the same
+func compareForSync(gnfdClient client.IClient, item *syncItem) (bool, string, error) {
+	objectInfo := item.objectInfo
+	if objectInfo == nil {
+		return true, "not exist in bucket", nil
+	}
+	if item.IsFolder {
+		return false, "folder exists", nil
+	}
+	if int64(objectInfo.PayloadSize) != item.Size {
+		return true, "size changed", nil
+	}
+	// the object of the same size may be being sealed after the last sync, it is not canceled and uploaded again
+	if objectInfo.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+		return false, "object not sealed yet", nil
+	}
+	if item.Size == 0 {
+		return false, "identical", nil
+	}
+
+	hashRoot, err := computeFileHashRoot(gnfdClient, item.FilePath)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to compute hash of %s: %v", item.FilePath, err)
+	}
+	if len(objectInfo.Checksums) == 0 || !bytes.Equal(objectInfo.Checksums[0], hashRoot) {
+		return true, "checksum changed", nil
+	}
+	return false, "identical", nil
+}
+
+// runSyncItem upload, download or delete the object or the local file of the sync item
+func runSyncItem(c context.Context, gnfdClient client.IClient, bucketName string, uploadFlag UploadFlag, item *syncItem) error {
+	switch item.Action {
+	case syncActionUpload:
+		return uploadObjectForSync(c, gnfdClient, bucketName, uploadFlag, item)
+	case syncActionUpdate:
+		if err := removeObjectForSync(c, gnfdClient, bucketName, item); err != nil {
+			return err
+		}
+		if err := uploadObjectForSync(c, gnfdClient, bucketName, uploadFlag, item); err != nil {
+			return fmt.Errorf("%w: %v", errSyncObjectLost, err)
+		}
+		return nil
+	case syncActionDownload:
+		if item.IsFolder {
+			return os.MkdirAll(item.FilePath, 0o700)
+		}
+		if err := os.MkdirAll(filepath.Dir(item.FilePath), 0o700); err != nil {
+			return err
+		}
+		if _, err := downloadObject(c, gnfdClient, "", bucketName, item.ObjectName, item.FilePath, sdktypes.GetObjectOptions{}); err != nil {
+			return err
+		}
+		return verifyDownloadedFile(gnfdClient, item.FilePath, item.objectInfo)
+	case syncActionDelete:
+		if item.objectInfo == nil {
+			return os.Remove(item.FilePath)
+		}
+		return removeObjectForSync(c, gnfdClient, bucketName, item)
+	default:
+		return nil
+	}
+}
+
+// uploadObjectForSync upload the file and wait for the object to be sealed, so that the next sync doesn't
+// see the uploaded object as not sealed
+func uploadObjectForSync(c context.Context, gnfdClient client.IClient, bucketName string, uploadFlag UploadFlag, item *syncItem) error {
+	if _, err := uploadFileByTask("", bucketName, item.ObjectName, item.FilePath, uploadFlag, gnfdClient, item.IsFolder, item.Size, false); err != nil {
+		return err
+	}
+	if item.IsFolder {
+		return nil
+	}
+	return waitObjectSealed(c, gnfdClient, bucketName, item.ObjectName, time.Hour)
+}
+
+// removeObjectForSync cancel the object if it has not been sealed, otherwise delete it
+func removeObjectForSync(c context.Context, gnfdClient client.IClient, bucketName string, item *syncItem) error {
+	var (
+		txnHash string
+		err     error
+	)
+	if item.objectInfo.GetObjectStatus() == storageTypes.OBJECT_STATUS_CREATED {
+		txnHash, err = gnfdClient.CancelCreateObject(c, bucketName, item.ObjectName, sdktypes.CancelCreateOption{TxOpts: &TxnOptionWithSyncMode})
+		if err != nil {
+			return err
+		}
+		return waitTxnStatus(gnfdClient, c, txnHash, "CancelCreateObject")
+	}
+
+	txnHash, err = gnfdClient.DeleteObject(c, bucketName, item.ObjectName, sdktypes.DeleteObjectOption{TxOpts: &TxnOptionWithSyncMode})
+	if err != nil {
+		return err
+	}
+	return waitTxnStatus(gnfdClient, c, txnHash, "DeleteObject")
+}
+
+func printSyncPlan(plan []*syncItem) {
+	counts := make(map[string]int)
+	format := "%-9s %10s  %-28s %s\n"
+	fmt.Println("sync plan:")
+	fmt.Printf(format, "action", "size", "reason", "object")
+	for _, item := range plan {
+		counts[item.Action]++
+		if item.Action == syncActionSkip {
+			continue
+		}
+		fmt.Printf(format, item.Action, getConvertSize(item.Size), item.Reason, item.ObjectName)
+	}
+	fmt.Printf("%d to upload, %d to update, %d to download, %d to delete, %d to skip\n\n",
+		counts[syncActionUpload], counts[syncActionUpdate], counts[syncActionDownload], counts[syncActionDelete], counts[syncActionSkip])
+}
+
+// listObjectInfos list all the objects under the prefix in a recursive way and return them by object name
+func listObjectInfos(gnfdClient client.IClient, c context.Context, bucketName, prefixName string) (map[string]*storageTypes.ObjectInfo, error) {
+	objectInfos := make(map[string]*storageTypes.ObjectInfo)
+	err := walkObjectsByPage(gnfdClient, c, bucketName, prefixName, true, func(listResult sdktypes.ListObjectsResult) error {
+		for _, object := range listResult.Objects {
+			if object.Removed {
+				continue
+			}
+			objectInfos[object.ObjectInfo.ObjectName] = object.ObjectInfo
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objectInfos, nil
+}
+
+func sortedObjectNames(objectInfos map[string]*storageTypes.ObjectInfo) []string {
+	names := make([]string, 0, len(objectInfos))
+	for name := range objectInfos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isObjectUrl check whether the path is an OBJECT-URL rather than a local path
+func isObjectUrl(path string) bool {
+	return strings.HasPrefix(path, urlPrefix)
+}
@@ -1,14 +1,103 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
 	"github.com/urfave/cli/v2"
+	"github.com/zkMeLabs/mechain-go-sdk/client"
+	sdktypes "github.com/zkMeLabs/mechain-go-sdk/types"
 )
 
+func cmdTaskList() *cli.Command {
+	return &cli.Command{
+		Name:      "ls",
+		Action:    listTasks,
+		Usage:     "list all the tasks",
+		ArgsUsage: "",
+		Description: `
+List the tasks under the home directory with the bucket, the folder, the creation time, the status
+and the number of the objects in every status
+
+Examples:
+$ mechain-cmd task ls
+# list the failed tasks which need to be retried
+$ mechain-cmd task ls --status failed
+$ mechain-cmd task ls --sortBy status`,
+		Flags: []cli.Flag{
+			&cli.GenericFlag{
+				Name: taskStatusFlag,
+				Value: &CmdEnumValue{
+					Enum: []string{TaskStatusCreate, TaskStatusFail, TaskStatusSuccess},
+				},
+				Usage: "only list the tasks in the status",
+			},
+			&cli.GenericFlag{
+				Name: sortByFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{"time", "status", "bucket"},
+					Default: "time",
+				},
+				Usage: "sort the tasks by the creation time, the status or the bucket",
+			},
+		},
+	}
+}
+
+func cmdTaskPrune() *cli.Command {
+	return &cli.Command{
+		Name:      "prune",
+		Action:    pruneTask,
+		Usage:     "delete the finished tasks in batch",
+		ArgsUsage: "",
+		Description: `
+Delete the successful or failed tasks matching all the set conditions, the running tasks and the tasks which are
+not finished yet are never pruned. The tasks can be pruned automatically after each successful task by setting
+the retention policy in the config file:
+
+[taskRetention]
+olderThan = "30d"
+keepLast = 20
+
+Examples:
+# delete the successful tasks created more than 7 days ago
+$ mechain-cmd task prune --olderThan 7d --status successful
+# keep the latest 10 finished tasks
+$ mechain-cmd task prune --keep-last 10
+$ mechain-cmd task prune --dryRun --olderThan 24h`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    olderThanFlag,
+				Aliases: []string{"older-than"},
+				Usage:   "only prune the tasks created before the duration, such as 36h or 7d",
+			},
+			&cli.GenericFlag{
+				Name: taskStatusFlag,
+				Value: &CmdEnumValue{
+					Enum: []string{TaskStatusSuccess, TaskStatusFail},
+				},
+				Usage: "only prune the tasks in the status",
+			},
+			&cli.IntFlag{
+				Name:    keepLastFlag,
+				Aliases: []string{"keep-last"},
+				Usage:   "keep the latest N tasks which match the status",
+			},
+			dryRunCliFlag(),
+			yesCliFlag(),
+		},
+	}
+}
+
 func cmdTaskStatus() *cli.Command {
 	return &cli.Command{
 		Name:      "status",
@@ -57,7 +146,10 @@ func cmdTaskRetry() *cli.Command {
 		ArgsUsage: "",
 		Description: `
 Examples:
-$ mechain-cmd task retry --taskId 123 `,
+$ mechain-cmd task retry --taskId 123 
+$ mechain-cmd task retry --parallel 8 --taskId 123 
+# re-plan the upload task from the current folder contents if some files have been changed since the task was built
+$ mechain-cmd task retry --onChange replan --taskId 123 `,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     taskIDFlag,
@@ -65,18 +157,303 @@ $ mechain-cmd task retry --taskId 123 `,
 				Usage:    "task id",
 				Required: true,
 			},
+			&cli.IntFlag{
+				Name:  parallelFlag,
+				Value: 1,
+				Usage: "the number of objects to be created and uploaded, or downloaded at the same time, the objects of a delete task are deleted one by one",
+			},
+			&cli.GenericFlag{
+				Name: onChangeFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{onChangeAbort, onChangeReplan},
+					Default: onChangeAbort,
+				},
+				Usage: "the action when the files of the upload task have been changed since the task was built, " +
+					"abort the retry or re-plan the task from the current folder contents",
+			},
 		},
 	}
 }
 
+func listTasks(ctx *cli.Context) error {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	states, err := loadAllTaskStates(homeDir)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	statusFilter := fmt.Sprintf("%s", ctx.Generic(taskStatusFlag))
+	tasks := make([]*TaskState, 0, len(states))
+	for _, state := range states {
+		if statusFilter == "" || state.Status == statusFilter {
+			tasks = append(tasks, state)
+		}
+	}
+
+	sortBy := fmt.Sprintf("%s", ctx.Generic(sortByFlag))
+	sort.SliceStable(tasks, func(i, j int) bool {
+		switch {
+		case sortBy == "status" && tasks[i].Status != tasks[j].Status:
+			return tasks[i].Status < tasks[j].Status
+		case sortBy == "bucket" && tasks[i].BucketName != tasks[j].BucketName:
+			return tasks[i].BucketName < tasks[j].BucketName
+		default:
+			return tasks[i].CreateTime < tasks[j].CreateTime
+		}
+	})
+
+	if isStructuredOutput() {
+		output := make([]taskOutput, 0, len(tasks))
+		for _, task := range tasks {
+			counts := task.CountObjectStatus()
+			objects := make(map[string]int)
+			for _, status := range taskObjectStatuses(task.GetKind()) {
+				objects[status] = counts[status]
+			}
+			output = append(output, taskOutput{
+				TaskID:     task.TaskID,
+				Kind:       task.GetKind(),
+				CreateTime: formatOutputTime(task.CreateTime),
+				Status:     task.Status,
+				Size:       taskDirSize(homeDir, task.TaskID),
+				BucketName: task.BucketName,
+				Folder:     task.location(),
+				Objects:    objects,
+			})
+		}
+		return printOutput(output)
+	}
+
+	format := fmt.Sprintf("%%-36s  %%-8s  %%-%ds  %%-10s  %%-9s  %%-20s  %%-30s  %%s\n", timeColumnWidth())
+	fmt.Printf(format, "task id", "kind", "create time", "status", "size", "bucket", "folder", "objects")
+	var totalSize int64
+	for _, task := range tasks {
+		size := taskDirSize(homeDir, task.TaskID)
+		totalSize += size
+		fmt.Printf(format, task.TaskID, task.GetKind(), formatUnixTime(task.CreateTime), task.Status,
+			getConvertSize(size), task.BucketName, task.location(), formatObjectStatusCounts(task))
+	}
+	fmt.Printf("\ntotal: %d tasks, %s\n", len(tasks), getConvertSize(totalSize))
+	return nil
+}
+
+func pruneTask(ctx *cli.Context) error {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	option := taskPruneOption{
+		Status:   fmt.Sprintf("%s", ctx.Generic(taskStatusFlag)),
+		KeepLast: ctx.Int(keepLastFlag),
+	}
+	if olderThan := ctx.String(olderThanFlag); olderThan != "" {
+		if option.OlderThan, err = parseDuration(olderThan); err != nil {
+			return toCmdErr(err)
+		}
+	}
+	if option.KeepLast < 0 {
+		return toCmdErr(invalidArgsError("--%s should not be negative", keepLastFlag))
+	}
+	if option.isEmpty() {
+		return toCmdErr(invalidArgsError("at least one of --%s, --%s and --%s should be set", olderThanFlag, taskStatusFlag, keepLastFlag))
+	}
+
+	states, err := loadAllTaskStates(homeDir)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	tasks := selectTasksToPrune(states, option, time.Now())
+	if len(tasks) == 0 {
+		fmt.Println("no task needs to be pruned")
+		return nil
+	}
+
+	if ctx.Bool(dryRunFlag) {
+		var totalSize int64
+		for _, task := range tasks {
+			size := taskDirSize(homeDir, task.TaskID)
+			totalSize += size
+			fmt.Printf("(dry run) prune %s  %-10s  %s  %s\n", task.TaskID, task.Status,
+				formatUnixTime(task.CreateTime), getConvertSize(size))
+		}
+		fmt.Printf("\n(dry run) %d tasks would be pruned, %s would be freed\n", len(tasks), getConvertSize(totalSize))
+		return nil
+	}
+
+	if len(tasks) > confirmThreshold {
+		confirmed, err := confirmOperation(ctx, fmt.Sprintf("prune %d tasks", len(tasks)))
+		if err != nil {
+			return toCmdErr(err)
+		}
+		if !confirmed {
+			fmt.Println("the operation is canceled")
+			return nil
+		}
+	}
+
+	pruned, freed := pruneTasks(homeDir, tasks)
+	fmt.Printf("pruned %d tasks, %s freed\n", pruned, getConvertSize(freed))
+	return nil
+}
+
+// taskPruneOption indicates the conditions of the tasks to be pruned
+type taskPruneOption struct {
+	OlderThan time.Duration
+	Status    string
+	KeepLast  int
+}
+
+// isEmpty report whether no condition is set, all the finished tasks would be pruned in this case
+func (o taskPruneOption) isEmpty() bool {
+	return o.OlderThan == 0 && o.Status == "" && o.KeepLast == 0
+}
+
+// pruneOption parse the retention policy of the config file
+func (r *taskRetentionConfig) pruneOption() (taskPruneOption, error) {
+	option := taskPruneOption{Status: r.Status, KeepLast: r.KeepLast}
+	if r.Status != "" && r.Status != TaskStatusSuccess && r.Status != TaskStatusFail {
+		return option, fmt.Errorf("invalid status %s, it should be %s or %s", r.Status, TaskStatusSuccess, TaskStatusFail)
+	}
+	if r.KeepLast < 0 {
+		return option, fmt.Errorf("invalid keepLast %d", r.KeepLast)
+	}
+	if r.OlderThan != "" {
+		olderThan, err := parseDuration(r.OlderThan)
+		if err != nil {
+			return option, err
+		}
+		option.OlderThan = olderThan
+	}
+	return option, nil
+}
+
+// selectTasksToPrune return the finished tasks matching the status, except the latest KeepLast ones of them,
+// and created before OlderThan
+func selectTasksToPrune(states []*TaskState, option taskPruneOption, now time.Time) []*TaskState {
+	candidates := make([]*TaskState, 0, len(states))
+	for _, state := range states {
+		if state.Status != TaskStatusSuccess && state.Status != TaskStatusFail {
+			continue
+		}
+		if option.Status != "" && state.Status != option.Status {
+			continue
+		}
+		candidates = append(candidates, state)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].CreateTime > candidates[j].CreateTime
+	})
+	if option.KeepLast >= len(candidates) {
+		return nil
+	}
+	candidates = candidates[option.KeepLast:]
+
+	tasks := make([]*TaskState, 0, len(candidates))
+	for _, state := range candidates {
+		if option.OlderThan > 0 && now.Sub(time.Unix(state.CreateTime, 0)) < option.OlderThan {
+			continue
+		}
+		tasks = append(tasks, state)
+	}
+	return tasks
+}
+
+// pruneTasks remove the directories of the tasks, the running tasks are skipped.
+// It returns the number of the pruned tasks and the freed bytes
+func pruneTasks(homeDir string, tasks []*TaskState) (int, int64) {
+	var pruned int
+	var freed int64
+	for _, task := range tasks {
+		size := taskDirSize(homeDir, task.TaskID)
+		// the lock is held until the directory is removed, the running task is not removed
+		if err := removeTaskDir(homeDir, task.TaskID); err != nil {
+			fmt.Printf("skip the task %s: %v\n", task.TaskID, err)
+			continue
+		}
+		pruned++
+		freed += size
+	}
+	return pruned, freed
+}
+
+// applyTaskRetention prune the tasks by the retention policy of the config file after the task succeeds,
+// the task itself is never pruned, and it is not failed if the pruning fails
+func applyTaskRetention(ctx *cli.Context, homeDir, taskID string) {
+	config, err := readCmdConfig(ctx)
+	if err != nil || config.TaskRetention == nil {
+		return
+	}
+	option, err := config.TaskRetention.pruneOption()
+	if err != nil {
+		fmt.Printf("invalid task retention in the config file: %v\n", err)
+		return
+	}
+	if option.isEmpty() {
+		return
+	}
+
+	states, err := loadAllTaskStates(homeDir)
+	if err != nil {
+		fmt.Printf("failed to apply the task retention: %v\n", err)
+		return
+	}
+	tasks := make([]*TaskState, 0)
+	for _, state := range selectTasksToPrune(states, option, time.Now()) {
+		if state.TaskID != taskID {
+			tasks = append(tasks, state)
+		}
+	}
+	if len(tasks) > 0 {
+		pruned, freed := pruneTasks(homeDir, tasks)
+		fmt.Printf("pruned %d tasks by the retention policy, %s freed\n", pruned, getConvertSize(freed))
+	}
+}
+
+// taskDirSize return the total size of the files in the task directory
+func taskDirSize(homeDir, taskID string) int64 {
+	var size int64
+	_ = filepath.WalkDir(filepath.Join(homeDir, "."+taskID), func(_ string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
 func getTaskStatus(ctx *cli.Context) error {
 	content, err := getTaskState(ctx)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("Folder: %s\n", content.FolderName)
+	if isStructuredOutput() {
+		output := taskStatusOutput{
+			TaskID:     content.TaskID,
+			Kind:       content.GetKind(),
+			Status:     content.Status,
+			Folder:     content.location(),
+			BucketName: content.BucketName,
+			Objects:    make([]taskObjectStatusOutput, 0, len(content.ObjectState)),
+		}
+		for index := 0; index < len(content.ObjectState); index++ {
+			state := content.ObjectState[index]
+			output.Objects = append(output.Objects, taskObjectStatusOutput{ObjectName: state.ObjectName, Status: state.Status, Comment: state.Comment})
+		}
+		return printOutput(output)
+	}
+	fmt.Printf("Kind: %s\n", content.GetKind())
+	fmt.Printf("Folder: %s\n", content.location())
 	fmt.Printf("Status: %s\n", content.Status)
-	for _, state := range content.ObjectState {
+	for index := 0; index < len(content.ObjectState); index++ {
+		state := content.ObjectState[index]
 		fmt.Printf("%s\n", fmt.Sprintf("%s %s %s", state.Status, state.ObjectName, state.Comment))
 	}
 	fmt.Println()
@@ -94,30 +471,465 @@ func deleteTask(ctx *cli.Context) error {
 	if !fileExists(taskFilePath) {
 		return toCmdErr(fmt.Errorf("task not found"))
 	}
-	err = os.RemoveAll(taskFilePath)
-	if err != nil {
+	// the running task can not be deleted
+	if err = removeTaskDir(homeDir, taskID); err != nil {
 		return toCmdErr(err)
 	}
 	return nil
 }
 
 func retryTask(ctx *cli.Context) error {
-	content, err := getTaskState(ctx)
+	taskID := ctx.String(taskIDFlag)
+	homeDir, err := getHomeDir(ctx)
 	if err != nil {
-		return err
+		return toCmdErr(err)
 	}
-	homeDir, err := getHomeDir(ctx)
+	if !fileExists(getTaskFilePath(homeDir, taskID)) {
+		return toCmdErr(fmt.Errorf("task not found"))
+	}
+	// the lock is held from loading the state until the task finishes, so that the state and the journal can't be
+	// changed by another process between the re-plan and the run
+	unlock, err := lockTaskDir(homeDir, taskID)
 	if err != nil {
 		return toCmdErr(err)
 	}
+	defer unlock()
+	content, err := loadTaskState(getTaskFilePath(homeDir, taskID))
+	if err != nil {
+		return toCmdErr(err)
+	}
+	content.dirLocked = true
+
 	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
 	if err != nil {
 		return err
 	}
 	fmt.Printf("task: %s\n", content.TaskID)
-	fmt.Printf("folder name: %s\n", content.FolderName)
+	fmt.Printf("%s task, folder name: %s\n", content.GetKind(), content.location())
 	fmt.Println("retrying...")
-	return uploadFolderByTask(ctx, homeDir, gnfdClient, content)
+	switch content.GetKind() {
+	case TaskKindUpload:
+		if err = checkUploadTaskChanges(ctx, gnfdClient, homeDir, content); err != nil {
+			return toCmdErr(err)
+		}
+		return uploadFolderByTask(ctx, homeDir, gnfdClient, content)
+	case TaskKindDownload:
+		if err = checkResumableDownloadLimit(content.DownloadFlag.Resumable); err != nil {
+			return toCmdErr(err)
+		}
+		return downloadFolderByTask(ctx, homeDir, gnfdClient, content)
+	case TaskKindDelete:
+		return deleteObjectsByTask(ctx, homeDir, gnfdClient, content)
+	case TaskKindWatch:
+		// the watch task is resumed with the options it was started with
+		return watchFolderByTask(ctx, homeDir, gnfdClient, content)
+	default:
+		return uploadFolderByTask(ctx, homeDir, gnfdClient, content)
+	}
+}
+
+// fileChange indicates the file of the upload task object which has been changed since the task was built
+type fileChange struct {
+	ObjectName string
+	Reason     string
+}
+
+// detectUploadTaskChanges compare the unsealed files of the upload task with the size, the modification time
+// and the hash which are recorded when the task is built
+func detectUploadTaskChanges(taskState *TaskState) []fileChange {
+	changes := make([]fileChange, 0)
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		object := taskState.ObjectState[index]
+		if object.UploadSingleFolder || object.Status == TaskObjectStatusSeal {
+			continue
+		}
+		if reason := compareTaskFile(object); reason != "" {
+			changes = append(changes, fileChange{ObjectName: object.ObjectName, Reason: reason})
+		}
+	}
+	return changes
+}
+
+// compareTaskFile return the reason if the file differs from the record of the task object, or empty if it is unchanged
+func compareTaskFile(object *UploadTaskObject) string {
+	info, err := os.Stat(object.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "file deleted"
+		}
+		return err.Error()
+	}
+	if info.Size() != object.ObjectSize {
+		return fmt.Sprintf("size changed from %d to %d", object.ObjectSize, info.Size())
+	}
+	// the hash decides whether the content is changed if it is recorded, the modification time is checked otherwise
+	if object.FileHash != "" {
+		fileHash, err := computeFileSHA256(object.FilePath)
+		if err != nil {
+			return err.Error()
+		}
+		if fileHash != object.FileHash {
+			return "content changed"
+		}
+		return ""
+	}
+	if object.ModTime != 0 && info.ModTime().UnixNano() != object.ModTime {
+		return "modification time changed"
+	}
+	return ""
+}
+
+// checkUploadTaskChanges report the changed files of the upload task before retrying it, the retry is aborted
+// or the task is re-planned from the current folder contents according to the --onChange flag
+func checkUploadTaskChanges(ctx *cli.Context, gnfdClient client.IClient, homeDir string, taskState *TaskState) error {
+	changes := detectUploadTaskChanges(taskState)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	fmt.Printf("%d files have been changed since the task was built:\n", len(changes))
+	for _, change := range changes {
+		fmt.Printf("%-30s %s\n", change.Reason, change.ObjectName)
+	}
+
+	if fmt.Sprintf("%s", ctx.Generic(onChangeFlag)) != onChangeReplan {
+		return fmt.Errorf("the retry is aborted, set --%s %s to re-plan the task from the current folder contents", onChangeFlag, onChangeReplan)
+	}
+	return replanUploadTask(gnfdClient, homeDir, taskState)
+}
+
+// replanUploadTask rebuild the objects of the upload task from the current folder contents, the status of the unchanged
+// files is kept, and the changed files will be uploaded again. The objects created with the former contents are canceled.
+// The caller should hold the lock of the task directory, so that the running task can not be re-planned
+func replanUploadTask(gnfdClient client.IClient, homeDir string, taskState *TaskState) error {
+	objectState, err := buildUploadTaskObjects(taskState.FolderName, taskState.BucketName, taskState.FilterFlag, taskState.RecordHash)
+	if err != nil {
+		return err
+	}
+
+	c, cancelReplan := context.WithCancel(globalContext)
+	defer cancelReplan()
+
+	formerObjects := make(map[string]*UploadTaskObject)
+	for _, object := range taskState.ObjectState {
+		formerObjects[object.ObjectName] = object
+	}
+
+	var added, changed int
+	for _, object := range objectState {
+		former, ok := formerObjects[object.ObjectName]
+		switch {
+		case !ok:
+			added++
+		case object.UploadSingleFolder || !isTaskFileChanged(former, object):
+			object.Status, object.Comment = former.Status, former.Comment
+		case former.Status == TaskObjectStatusSeal:
+			// the sealed object can not be updated by the task, it needs to be uploaded again by "object put"
+			object.Status, object.Comment = former.Status, "sealed with the former content of the file"
+		default:
+			changed++
+			objectDetail, err := gnfdClient.HeadObject(c, object.BucketName, object.ObjectName)
+			if err == nil && objectDetail.ObjectInfo.GetObjectStatus() == storageTypes.OBJECT_STATUS_CREATED {
+				txnHash, err := gnfdClient.CancelCreateObject(c, object.BucketName, object.ObjectName,
+					sdktypes.CancelCreateOption{TxOpts: &TxnOptionWithSyncMode})
+				if err != nil {
+					return fmt.Errorf("failed to cancel the object %s created with the former content: %v", object.ObjectName, err)
+				}
+				// the retry should not see the former object, otherwise the new content is uploaded with its checksums
+				if err = waitTxnStatus(gnfdClient, c, txnHash, "CancelCreateObject"); err != nil {
+					return fmt.Errorf("failed to cancel the object %s created with the former content: %w", object.ObjectName, err)
+				}
+			}
+		}
+	}
+
+	// the journal records the transitions of the former objects, it is not valid for the new plan. It is removed before
+	// the new plan is saved, otherwise it would be replayed onto the new objects if the process exits in between
+	if err = os.Remove(getTaskJournalPath(homeDir, taskState.TaskID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	taskState.Lock.Lock()
+	taskState.ObjectState = objectState
+	taskState.Status = TaskStatusCreate
+	taskState.Lock.Unlock()
+	if err = saveTaskState(homeDir, taskState); err != nil {
+		return err
+	}
+
+	fmt.Printf("the task is re-planned: %d objects, %d new files, %d changed files\n", len(objectState), added, changed)
+	return nil
+}
+
+// isTaskFileChanged report whether the file of the task object is changed, the hash decides it if both of them record it
+func isTaskFileChanged(former, current *UploadTaskObject) bool {
+	if former.ObjectSize != current.ObjectSize {
+		return true
+	}
+	if former.FileHash != "" && current.FileHash != "" {
+		return former.FileHash != current.FileHash
+	}
+	return former.ModTime != 0 && former.ModTime != current.ModTime
+}
+
+// printTaskSubmitted print the task ID and the commands to manage the task
+func printTaskSubmitted(kind, taskID string) {
+	fmt.Println("================================================")
+	fmt.Printf("Your batch %s is submitted as a task, task ID is %s\n", kind, taskID)
+	fmt.Println("You can check your task status and progress by using cmd as below:\n\n- List all your tasks: ./mechain-cmd task ls\n- Check status: ./mechain-cmd task status --taskId taskID\n- Retry (in case this process is killed accidentally): ./mechain-cmd task retry --taskId taskID\n- Delete task: ./mechain-cmd task delete --taskId taskID\n\n>>================================================")
+}
+
+// runObjectTask run the unfinished objects of the download or delete task by the handler, the state of the task is synchronized
+// to the state file periodically, and the task is successful if none of the objects fails
+func runObjectTask(ctx *cli.Context, homeDir string, taskState *TaskState, parallel int,
+	handler func(object *UploadTaskObject) (string, error),
+) error {
+	finishTask, err := startTask(homeDir, taskState)
+	if err != nil {
+		return err
+	}
+	defer finishTask()
+
+	signalCtx, cancel := context.WithCancel(ctx.Context)
+	syncDone := make(chan struct{})
+	go func() {
+		stateSync(signalCtx, homeDir, taskState)
+		close(syncDone)
+	}()
+
+	pool := NewPool(parallel)
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		if isTaskObjectFinished(taskState.GetObjectStatus(index)) {
+			continue
+		}
+
+		pool.Add(1)
+		go func(index int, object *UploadTaskObject) {
+			defer pool.Done()
+			taskState.StartObject(index)
+			status, err := handler(object)
+			if err != nil {
+				taskState.FinishObject(index, "", 0)
+				taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
+				emitObjectEvent(progressEventFailed, taskState.TaskID, object.BucketName, object.ObjectName, err)
+				fmt.Printf("\r%s %s %s\n", TaskObjectStatusFailed, object.ObjectName, err.Error())
+				return
+			}
+			var transferred int64
+			if status == TaskObjectStatusDownloaded {
+				transferred = object.ObjectSize
+			}
+			taskState.FinishObject(index, "", transferred)
+			taskState.UpdateObjectState(index, status, "")
+			fmt.Printf("\r%s %s\n", status, object.ObjectName)
+		}(index, taskState.ObjectState[index])
+	}
+	pool.Wait()
+	cancel()
+	<-syncDone
+
+	counts := taskState.CountObjectStatus()
+	if counts[TaskObjectStatusFailed] > 0 {
+		taskState.SetStatus(TaskStatusFail)
+	} else {
+		taskState.SetStatus(TaskStatusSuccess)
+	}
+	if err := saveTaskState(homeDir, taskState); err != nil {
+		return err
+	}
+	emitTaskDone(taskState)
+
+	fmt.Printf("\ntask %s %s: %s\n", taskState.TaskID, taskState.Status, formatObjectStatusCounts(taskState))
+	if counts[TaskObjectStatusFailed] > 0 {
+		return taskFailedError(taskState)
+	}
+	applyTaskRetention(ctx, homeDir, taskState.TaskID)
+	return nil
+}
+
+// taskFailedError return the error of the task whose objects failed, with the command to retry the task
+func taskFailedError(taskState *TaskState) error {
+	return fmt.Errorf("%d of %d objects failed, the task can be retried by: ./mechain-cmd task retry --taskId %s",
+		taskState.CountObjectStatus()[TaskObjectStatusFailed], len(taskState.ObjectState), taskState.TaskID)
+}
+
+// isTaskObjectFinished report whether the task object needs no more running
+func isTaskObjectFinished(status string) bool {
+	switch status {
+	case TaskObjectStatusSeal, TaskObjectStatusDownloaded, TaskObjectStatusDeleted, TaskObjectStatusSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// taskObjectStatuses return the statuses of the objects of the task kind in the order of the progress
+func taskObjectStatuses(kind string) []string {
+	switch kind {
+	case TaskKindDownload:
+		return []string{TaskObjectStatusWaitForDownload, TaskObjectStatusDownloaded, TaskObjectStatusSkipped, TaskObjectStatusFailed}
+	case TaskKindDelete:
+		return []string{TaskObjectStatusWaitForDelete, TaskObjectStatusDeleted, TaskObjectStatusFailed}
+	default:
+		return []string{TaskObjectStatusWaitForUpload, TaskObjectStatusCreated, TaskObjectStatusSeal, TaskObjectStatusFailed}
+	}
+}
+
+// formatObjectStatusCounts return the number of the task objects in every status, like "wait_for_upload:1 created:2"
+func formatObjectStatusCounts(taskState *TaskState) string {
+	counts := taskState.CountObjectStatus()
+	items := make([]string, 0)
+	for _, status := range taskObjectStatuses(taskState.GetKind()) {
+		items = append(items, fmt.Sprintf("%s:%d", status, counts[status]))
+	}
+	return strings.Join(items, " ")
+}
+
+// location return the local folder of the upload or download task, or the prefix of the delete task
+func (t *TaskState) location() string {
+	if t.FolderName != "" {
+		return t.FolderName
+	}
+	return t.Prefix
+}
+
+// getTaskFilePath return the path of the state file of the task
+func getTaskFilePath(homeDir, taskID string) string {
+	return filepath.Join(homeDir, fmt.Sprintf("/.%s/state", taskID))
+}
+
+// saveTaskState write the task state to the state file atomically, then the journal is compacted since its transitions
+// are included by the saved state. The lock is held until then, so that the transitions in between are not dropped
+func saveTaskState(homeDir string, taskState *TaskState) error {
+	taskState.Lock.Lock()
+	defer taskState.Lock.Unlock()
+	content, err := json.Marshal(taskState)
+	if err != nil {
+		return err
+	}
+	if err = writeFileAtomic(getTaskFilePath(homeDir, taskState.TaskID), content); err != nil {
+		return err
+	}
+	return taskState.truncateJournal()
+}
+
+// taskJournalEntry is a line of the task journal, which records a status transition of the task or one of its objects
+type taskJournalEntry struct {
+	Time int64 `json:"time"`
+	// Index is the index of the object, or taskJournalStatusIndex for the status of the task
+	Index   int    `json:"index"`
+	Status  string `json:"status"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// taskJournalStatusIndex is the index of the journal entry recording the status of the task
+const taskJournalStatusIndex = -1
+
+// getTaskJournalPath return the path of the journal file of the task
+func getTaskJournalPath(homeDir, taskID string) string {
+	return filepath.Join(homeDir, fmt.Sprintf("/.%s/journal", taskID))
+}
+
+// openJournal open the journal of the task, the status transitions are appended to it until it is closed
+func (t *TaskState) openJournal(homeDir string) error {
+	journalPath := getTaskJournalPath(homeDir, t.TaskID)
+	if err := os.MkdirAll(filepath.Dir(journalPath), 0o700); err != nil {
+		return err
+	}
+	journal, err := os.OpenFile(journalPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	t.Lock.Lock()
+	t.journal = journal
+	t.Lock.Unlock()
+	return nil
+}
+
+// closeJournal stop recording the status transitions
+func (t *TaskState) closeJournal() {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	if t.journal != nil {
+		_ = t.journal.Close()
+		t.journal = nil
+	}
+}
+
+// truncateJournal drop the transitions of the journal which are included by the saved state, the caller should hold
+// the lock. The journal is reopened with O_TRUNC since the file opened for appending can't be truncated on windows
+func (t *TaskState) truncateJournal() error {
+	if t.journal == nil {
+		return nil
+	}
+	journalPath := t.journal.Name()
+	_ = t.journal.Close()
+	journal, err := os.OpenFile(journalPath, os.O_CREATE|os.O_TRUNC|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.journal = nil
+		return err
+	}
+	t.journal = journal
+	return nil
+}
+
+// appendJournal record the status transition, the caller should hold the lock
+func (t *TaskState) appendJournal(index int, status, comment string) {
+	if t.journal == nil {
+		return
+	}
+	line, err := json.Marshal(taskJournalEntry{Time: time.Now().Unix(), Index: index, Status: status, Comment: comment})
+	if err != nil {
+		return
+	}
+	_, _ = t.journal.Write(append(line, '\n'))
+}
+
+// replayTaskJournal apply the transitions of the journal to the task state, since the journal is written when the
+// transitions happen, it is newer than the state file which is only synchronized periodically
+func replayTaskJournal(journalPath string, state *TaskState) error {
+	journal, err := os.Open(journalPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer journal.Close()
+
+	scanner := bufio.NewScanner(journal)
+	for scanner.Scan() {
+		var entry taskJournalEntry
+		// the last line may be truncated if the process crashed while writing it
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Index == taskJournalStatusIndex {
+			state.Status = entry.Status
+		} else if object, ok := state.ObjectState[entry.Index]; ok {
+			object.Status, object.Comment = entry.Status, entry.Comment
+		}
+	}
+	return scanner.Err()
+}
+
+// startTask lock the task directory and open the journal before running the task, the returned function
+// should be called to release them after the task finishes. The lock held by the caller is kept as it is
+func startTask(homeDir string, taskState *TaskState) (func(), error) {
+	unlock := func() {}
+	if !taskState.dirLocked {
+		var err error
+		if unlock, err = lockTaskDir(homeDir, taskState.TaskID); err != nil {
+			return nil, err
+		}
+	}
+	if err := taskState.openJournal(homeDir); err != nil {
+		unlock()
+		return nil, err
+	}
+	return func() {
+		taskState.closeJournal()
+		unlock()
+	}, nil
 }
 
 func getTaskState(ctx *cli.Context) (*TaskState, error) {
@@ -126,23 +938,66 @@ func getTaskState(ctx *cli.Context) (*TaskState, error) {
 	if err != nil {
 		return nil, toCmdErr(err)
 	}
-	taskFileName := fmt.Sprintf("/.%s/state", taskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
+	taskFilePath := getTaskFilePath(homeDir, taskID)
 	if !fileExists(taskFilePath) {
 		return nil, toCmdErr(fmt.Errorf("task not found"))
 	}
-	value, err := readFile(taskFilePath)
+	content, err := loadTaskState(taskFilePath)
 	if err != nil {
 		return nil, toCmdErr(err)
 	}
-	var content TaskState
+	return content, nil
+}
+
+// loadTaskState read and decode the task state file
+func loadTaskState(taskFilePath string) (*TaskState, error) {
+	value, err := readFile(taskFilePath)
+	if err != nil {
+		return nil, err
+	}
+	content := TaskState{Lock: new(sync.Mutex)}
 	err = json.Unmarshal(value, &content)
 	if err != nil {
-		return nil, toCmdErr(err)
+		return nil, err
+	}
+	if err = replayTaskJournal(filepath.Join(filepath.Dir(taskFilePath), "journal"), &content); err != nil {
+		return nil, err
+	}
+	// the tasks created by the former versions do not record the creation time, use the modification time instead
+	if content.CreateTime == 0 {
+		if info, err := os.Stat(filepath.Dir(taskFilePath)); err == nil {
+			content.CreateTime = info.ModTime().Unix()
+		}
 	}
 	return &content, nil
 }
 
+// loadAllTaskStates scan the task directories under the home directory, the broken state files are skipped
+func loadAllTaskStates(homeDir string) ([]*TaskState, error) {
+	entries, err := os.ReadDir(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*TaskState, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		taskFilePath := filepath.Join(homeDir, entry.Name(), "state")
+		if !fileExists(taskFilePath) {
+			continue
+		}
+		state, err := loadTaskState(taskFilePath)
+		if err != nil {
+			// the warning is printed to stderr, so that the structured output of "task ls" can still be parsed
+			fmt.Fprintf(os.Stderr, "failed to load the task %s: %v\n", strings.TrimPrefix(entry.Name(), "."), err)
+			continue
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path) // os.Stat获取文件信息
 	return !os.IsNotExist(err)
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	reportFormatJSON = "json"
+	reportFormatCSV  = "csv"
+)
+
+func cmdTaskReport() *cli.Command {
+	return &cli.Command{
+		Name:      "report",
+		Action:    reportTask,
+		Usage:     "export the report of the task",
+		ArgsUsage: "",
+		Description: `
+Export the report of the task to the standard output, it contains the timing, the transferred bytes, the transaction hash,
+the seal time and the retry count of every object, together with the aggregate stats of the task: the total throughput,
+the p50 and p95 seal latency and the failure reasons grouped by error.
+The times are in RFC3339 format, the durations are in milliseconds.
+
+Examples:
+$ mechain-cmd task report --taskId 123
+$ mechain-cmd task report --format csv --taskId 123 > report.csv`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     taskIDFlag,
+				Value:    "",
+				Usage:    "task id",
+				Required: true,
+			},
+			&cli.GenericFlag{
+				Name: formatFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{reportFormatJSON, reportFormatCSV},
+					Default: reportFormatJSON,
+				},
+				Usage: "the format of the report, the objects and the stats are written as separate tables in csv format",
+			},
+		},
+	}
+}
+
+// taskReport is the report of the task which is exported by "task report"
+type taskReport struct {
+	TaskID     string              `json:"task_id"`
+	Kind       string              `json:"kind"`
+	Status     string              `json:"status"`
+	BucketName string              `json:"bucket_name"`
+	Folder     string              `json:"folder"`
+	CreateTime string              `json:"create_time"`
+	Stats      taskReportStats     `json:"stats"`
+	Failures   []taskReportFailure `json:"failures"`
+	Objects    []taskReportObject  `json:"objects"`
+}
+
+// taskReportStats is the aggregate stats of the task objects
+type taskReportStats struct {
+	TotalObjects int            `json:"total_objects"`
+	StatusCounts map[string]int `json:"status_counts"`
+	TotalBytes   int64          `json:"total_bytes"`
+	// DurationMs is the time from the start of the first object to the end of the last one
+	DurationMs       int64   `json:"duration_ms"`
+	ThroughputBps    float64 `json:"throughput_bytes_per_second"`
+	SealLatencyP50Ms int64   `json:"seal_latency_p50_ms"`
+	SealLatencyP95Ms int64   `json:"seal_latency_p95_ms"`
+	TotalRetries     int     `json:"total_retries"`
+}
+
+// taskReportFailure is the number of the failed objects with the same error
+type taskReportFailure struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// taskReportObject is the report of a task object
+type taskReportObject struct {
+	ObjectName    string `json:"object_name"`
+	FilePath      string `json:"file_path"`
+	Size          int64  `json:"size"`
+	Status        string `json:"status"`
+	Comment       string `json:"comment"`
+	StartTime     string `json:"start_time"`
+	EndTime       string `json:"end_time"`
+	SealTime      string `json:"seal_time"`
+	DurationMs    int64  `json:"duration_ms"`
+	SealLatencyMs int64  `json:"seal_latency_ms"`
+	Bytes         int64  `json:"bytes"`
+	TxHash        string `json:"tx_hash"`
+	RetryCount    int    `json:"retry_count"`
+}
+
+func reportTask(ctx *cli.Context) error {
+	content, err := getTaskState(ctx)
+	if err != nil {
+		return err
+	}
+	report := buildTaskReport(content)
+
+	if fmt.Sprintf("%s", ctx.Generic(formatFlag)) == reportFormatCSV {
+		err = writeTaskReportCSV(os.Stdout, report)
+	} else {
+		err = writeTaskReportJSON(os.Stdout, report)
+	}
+	if err != nil {
+		return toCmdErr(err)
+	}
+	return nil
+}
+
+// buildTaskReport collect the metrics of the task objects and compute the aggregate stats
+func buildTaskReport(taskState *TaskState) *taskReport {
+	report := &taskReport{
+		TaskID:     taskState.TaskID,
+		Kind:       taskState.GetKind(),
+		Status:     taskState.Status,
+		BucketName: taskState.BucketName,
+		Folder:     taskState.location(),
+		CreateTime: time.Unix(taskState.CreateTime, 0).In(displayTimeLocation).Format(time.RFC3339),
+		Stats: taskReportStats{
+			TotalObjects: len(taskState.ObjectState),
+			StatusCounts: taskState.CountObjectStatus(),
+		},
+		Failures: make([]taskReportFailure, 0),
+		Objects:  make([]taskReportObject, 0, len(taskState.ObjectState)),
+	}
+
+	var firstStart, lastEnd int64
+	sealLatencies := make([]int64, 0)
+	failures := make(map[string]int)
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		object := taskState.ObjectState[index]
+		item := taskReportObject{
+			ObjectName: object.ObjectName,
+			FilePath:   object.FilePath,
+			Size:       object.ObjectSize,
+			Status:     object.Status,
+			Comment:    object.Comment,
+			StartTime:  formatReportTime(object.StartTime),
+			EndTime:    formatReportTime(object.EndTime),
+			SealTime:   formatReportTime(object.SealTime),
+			Bytes:      object.Bytes,
+			TxHash:     object.TxHash,
+			RetryCount: object.RetryCount,
+		}
+		if object.StartTime != 0 && object.EndTime >= object.StartTime {
+			item.DurationMs = object.EndTime - object.StartTime
+		}
+		// the seal latency is the time from the end of the upload to the seal of the object
+		if object.EndTime != 0 && object.SealTime >= object.EndTime {
+			item.SealLatencyMs = object.SealTime - object.EndTime
+			sealLatencies = append(sealLatencies, item.SealLatencyMs)
+		}
+		report.Objects = append(report.Objects, item)
+
+		report.Stats.TotalBytes += object.Bytes
+		report.Stats.TotalRetries += object.RetryCount
+		if object.StartTime != 0 && (firstStart == 0 || object.StartTime < firstStart) {
+			firstStart = object.StartTime
+		}
+		if object.EndTime > lastEnd {
+			lastEnd = object.EndTime
+		}
+		if object.Status == TaskObjectStatusFailed {
+			failures[object.Comment]++
+		}
+	}
+
+	if firstStart != 0 && lastEnd > firstStart {
+		report.Stats.DurationMs = lastEnd - firstStart
+		report.Stats.ThroughputBps = math.Round(float64(report.Stats.TotalBytes)*1000/float64(report.Stats.DurationMs)*100) / 100
+	}
+	sort.Slice(sealLatencies, func(i, j int) bool { return sealLatencies[i] < sealLatencies[j] })
+	report.Stats.SealLatencyP50Ms = percentile(sealLatencies, 50)
+	report.Stats.SealLatencyP95Ms = percentile(sealLatencies, 95)
+
+	for reason, count := range failures {
+		report.Failures = append(report.Failures, taskReportFailure{Reason: reason, Count: count})
+	}
+	sort.Slice(report.Failures, func(i, j int) bool {
+		if report.Failures[i].Count != report.Failures[j].Count {
+			return report.Failures[i].Count > report.Failures[j].Count
+		}
+		return report.Failures[i].Reason < report.Failures[j].Reason
+	})
+	return report
+}
+
+// percentile return the nearest-rank percentile of the sorted values, or 0 if there is no value
+func percentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// formatReportTime format the unix milliseconds in RFC3339 in the time zone, or empty if the time is not recorded.
+// The report keeps RFC3339 regardless of the time format so that it can be parsed
+func formatReportTime(unixMilli int64) string {
+	if unixMilli == 0 {
+		return ""
+	}
+	return time.UnixMilli(unixMilli).In(displayTimeLocation).Format(time.RFC3339Nano)
+}
+
+func writeTaskReportJSON(w io.Writer, report *taskReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// writeTaskReportCSV write the objects, the stats and the failures of the report as three tables separated by empty lines
+func writeTaskReportCSV(w io.Writer, report *taskReport) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"object_name", "file_path", "size", "status", "comment", "start_time", "end_time", "seal_time",
+		"duration_ms", "seal_latency_ms", "bytes", "tx_hash", "retry_count"}}
+	for _, object := range report.Objects {
+		records = append(records, []string{object.ObjectName, object.FilePath, strconv.FormatInt(object.Size, 10), object.Status,
+			object.Comment, object.StartTime, object.EndTime, object.SealTime, strconv.FormatInt(object.DurationMs, 10),
+			strconv.FormatInt(object.SealLatencyMs, 10), strconv.FormatInt(object.Bytes, 10), object.TxHash,
+			strconv.Itoa(object.RetryCount)})
+	}
+
+	stats := report.Stats
+	records = append(records, nil, []string{"stat", "value"},
+		[]string{"task_id", report.TaskID},
+		[]string{"kind", report.Kind},
+		[]string{"status", report.Status},
+		[]string{"bucket_name", report.BucketName},
+		[]string{"folder", report.Folder},
+		[]string{"create_time", report.CreateTime},
+		[]string{"total_objects", strconv.Itoa(stats.TotalObjects)},
+		[]string{"total_bytes", strconv.FormatInt(stats.TotalBytes, 10)},
+		[]string{"duration_ms", strconv.FormatInt(stats.DurationMs, 10)},
+		[]string{"throughput_bytes_per_second", strconv.FormatFloat(stats.ThroughputBps, 'f', 2, 64)},
+		[]string{"seal_latency_p50_ms", strconv.FormatInt(stats.SealLatencyP50Ms, 10)},
+		[]string{"seal_latency_p95_ms", strconv.FormatInt(stats.SealLatencyP95Ms, 10)},
+		[]string{"total_retries", strconv.Itoa(stats.TotalRetries)})
+	statuses := make([]string, 0, len(stats.StatusCounts))
+	for status := range stats.StatusCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		records = append(records, []string{"status_" + status, strconv.Itoa(stats.StatusCounts[status])})
+	}
+
+	records = append(records, nil, []string{"failure_reason", "count"})
+	for _, failure := range report.Failures {
+		records = append(records, []string{failure.Reason, strconv.Itoa(failure.Count)})
+	}
+
+	for _, record := range records {
+		// the empty record separates the tables
+		if record == nil {
+			writer.Flush()
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
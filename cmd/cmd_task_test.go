@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_selectTasksToPrune(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	daysAgo := func(days int) int64 {
+		return now.Add(-time.Duration(days) * 24 * time.Hour).Unix()
+	}
+	states := []*TaskState{
+		{TaskID: "success-1d", Status: TaskStatusSuccess, CreateTime: daysAgo(1)},
+		{TaskID: "fail-3d", Status: TaskStatusFail, CreateTime: daysAgo(3)},
+		{TaskID: "create-5d", Status: TaskStatusCreate, CreateTime: daysAgo(5)},
+		{TaskID: "success-10d", Status: TaskStatusSuccess, CreateTime: daysAgo(10)},
+		{TaskID: "create-20d", Status: TaskStatusCreate, CreateTime: daysAgo(20)},
+		{TaskID: "success-30d", Status: TaskStatusSuccess, CreateTime: daysAgo(30)},
+	}
+
+	tests := []struct {
+		name   string
+		option taskPruneOption
+		want   []string
+	}{
+		{name: "no condition", option: taskPruneOption{}, want: []string{"success-1d", "fail-3d", "success-10d", "success-30d"}},
+		{name: "status", option: taskPruneOption{Status: TaskStatusFail}, want: []string{"fail-3d"}},
+		{name: "older than", option: taskPruneOption{OlderThan: 7 * 24 * time.Hour}, want: []string{"success-10d", "success-30d"}},
+		{name: "older than exactly", option: taskPruneOption{OlderThan: 10 * 24 * time.Hour}, want: []string{"success-10d", "success-30d"}},
+		{name: "keep last", option: taskPruneOption{KeepLast: 2}, want: []string{"success-10d", "success-30d"}},
+		{name: "keep last of the status", option: taskPruneOption{KeepLast: 1, Status: TaskStatusSuccess}, want: []string{"success-10d", "success-30d"}},
+		{name: "keep last all", option: taskPruneOption{KeepLast: 4}, want: nil},
+		{name: "keep last more than all", option: taskPruneOption{KeepLast: 10}, want: nil},
+		// the kept tasks are counted before the older ones are selected, so the recent tasks don't shrink the kept ones
+		{name: "keep last and older than", option: taskPruneOption{KeepLast: 3, OlderThan: 2 * 24 * time.Hour}, want: []string{"success-30d"}},
+		{name: "older than all", option: taskPruneOption{OlderThan: 60 * 24 * time.Hour}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, state := range selectTasksToPrune(states, tt.option, now) {
+				got = append(got, state.TaskID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectTasksToPrune() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_saveTaskStateCompactsJournal(t *testing.T) {
+	homeDir := t.TempDir()
+	state := &TaskState{
+		Lock:   new(sync.Mutex),
+		TaskID: "journal",
+		ObjectState: map[int]*UploadTaskObject{
+			0: {ObjectName: "a", Status: TaskObjectStatusWaitForUpload},
+			1: {ObjectName: "b", Status: TaskObjectStatusWaitForUpload},
+		},
+	}
+	if err := state.openJournal(homeDir); err != nil {
+		t.Fatal(err)
+	}
+	defer state.closeJournal()
+
+	state.UpdateObjectState(0, TaskObjectStatusSeal, "")
+	if err := saveTaskState(homeDir, state); err != nil {
+		t.Fatal(err)
+	}
+	journalPath := getTaskJournalPath(homeDir, state.TaskID)
+	if info, err := os.Stat(journalPath); err != nil || info.Size() != 0 {
+		t.Fatalf("the journal is not compacted after the state is saved: %v", err)
+	}
+
+	// the transitions after the save are still recorded by the journal
+	state.UpdateObjectState(1, TaskObjectStatusFailed, "failed")
+	loaded, err := loadTaskState(getTaskFilePath(homeDir, state.TaskID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.ObjectState[0].Status; got != TaskObjectStatusSeal {
+		t.Errorf("status of a = %s, want %s", got, TaskObjectStatusSeal)
+	}
+	if got := loaded.ObjectState[1].Status; got != TaskObjectStatusFailed {
+		t.Errorf("status of b = %s, want %s", got, TaskObjectStatusFailed)
+	}
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	storageTypes "github.com/evmos/evmos/v12/x/storage/types"
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+	"github.com/zkMeLabs/mechain-go-sdk/client"
+)
+
+// cmdWatchObject return the command to upload the new files of a local folder continuously
+func cmdWatchObject() *cli.Command {
+	return &cli.Command{
+		Name:      "watch",
+		Action:    watchFolder,
+		Usage:     "watch a local folder and upload the new files continuously",
+		ArgsUsage: "FOLDER-PATH mechain://<bucket-name>/<prefix>",
+		Description: `
+Scan the local folder periodically and upload the new files under the prefix of the bucket, the relative path of the file
+is kept in the object name. A file is uploaded only after its size and modification time stay unchanged for the settle
+duration, so the files which are still being written are not uploaded. The failed uploads are retried on the next scans.
+
+The files are added to a watch task as the task objects, the state is kept in the task directory, so the same watch
+task is resumed if the command is run again with the same folder, bucket and prefix, or by "task retry".
+The command runs until it is interrupted, the running uploads are waited for before exiting.
+
+Examples:
+$ mechain-cmd object watch ./drop mechain://bucket-name/instrument-a
+$ mechain-cmd object watch --settle 30s --exclude "*.tmp" ./drop mechain://bucket-name/instrument-a`,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  secondarySPFlag,
+				Value: "",
+				Usage: "indicate the Secondary SP addr string list, input like addr1,addr2,addr3",
+			},
+			&cli.StringFlag{
+				Name:  contentTypeFlag,
+				Value: "",
+				Usage: "indicate object content-type",
+			},
+			&cli.GenericFlag{
+				Name: visibilityFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{publicReadType, privateType, inheritType},
+					Default: inheritType,
+				},
+				Usage: "set visibility of the object",
+			},
+			&cli.Uint64Flag{
+				Name: partSizeFlag,
+				// the default part size is 32M
+				Value: 32 * 1024 * 1024,
+				Usage: "indicate the resumable upload 's part size, uploading a large file in multiple parts. " +
+					"The part size is an integer multiple of the segment size.",
+			},
+			&cli.StringFlag{
+				Name:  tagFlag,
+				Value: "",
+				Usage: "set one or more tags of the object. The tag value is key-value pairs in json array format. E.g. [{\"key\":\"key1\",\"value\":\"value1\"},{\"key\":\"key2\",\"value\":\"value2\"}]",
+			},
+			&cli.DurationFlag{
+				Name:  settleFlag,
+				Value: 10 * time.Second,
+				Usage: "the duration the size and the modification time of a file should stay unchanged before it is uploaded",
+			},
+			&cli.DurationFlag{
+				Name:  intervalFlag,
+				Value: 5 * time.Second,
+				Usage: "the interval of scanning the folder and checking the seal status of the uploaded objects",
+			},
+			&cli.IntFlag{
+				Name:  retriesFlag,
+				Value: 3,
+				Usage: "the number of the retries of a failed upload",
+			},
+			&cli.IntFlag{
+				Name:  parallelFlag,
+				Value: defaultParallelNum,
+				Usage: "the number of objects to be created and uploaded at the same time",
+			},
+		}, filterFlags()...),
+	}
+}
+
+func watchFolder(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return toCmdErr(invalidArgsError("the args should contain the folder path and the bucket url"))
+	}
+	folderName, err := filepath.Abs(ctx.Args().Get(0))
+	if err != nil {
+		return toCmdErr(err)
+	}
+	fileInfo, err := os.Stat(folderName)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if !fileInfo.IsDir() {
+		return toCmdErr(invalidArgsError("%s is not a folder", folderName))
+	}
+
+	bucketName, prefix, err := ParseBucketAndPrefix(ctx.Args().Get(1))
+	if err != nil {
+		return toCmdErr(err)
+	}
+	// the prefix is regarded as a folder of the bucket
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	if ctx.Int(retriesFlag) < 0 || ctx.Duration(intervalFlag) <= 0 {
+		return toCmdErr(invalidArgsError("--%s should not be negative and --%s should be positive", retriesFlag, intervalFlag))
+	}
+
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	c, cancelWatch := context.WithCancel(globalContext)
+	defer cancelWatch()
+
+	if _, err = gnfdClient.HeadBucket(c, bucketName); err != nil {
+		return toCmdErr(ErrBucketNotExist)
+	}
+
+	taskState, err := findWatchTask(homeDir, folderName, bucketName, prefix)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if taskState == nil {
+		taskState = &TaskState{
+			Lock:        new(sync.Mutex),
+			ObjectState: make(map[int]*UploadTaskObject),
+			TaskID:      uuid.New().String(),
+			Kind:        TaskKindWatch,
+			FolderName:  folderName,
+			BucketName:  bucketName,
+			Prefix:      prefix,
+			Status:      TaskStatusCreate,
+			CreateTime:  time.Now().Unix(),
+		}
+		printTaskSubmitted(TaskKindWatch, taskState.TaskID)
+	} else {
+		fmt.Printf("resume the watch task %s, %d objects have been added\n", taskState.TaskID, len(taskState.ObjectState))
+	}
+
+	// the options of the command take effect when the task is resumed
+	taskState.FilterFlag = getFilterFlag(ctx, filepath.Join(folderName, defaultIgnoreFileName))
+	if taskState.Flag, err = getUploadFlag(ctx); err != nil {
+		return toCmdErr(err)
+	}
+	taskState.WatchFlag = WatchFlag{
+		Settle:   ctx.Duration(settleFlag),
+		Interval: ctx.Duration(intervalFlag),
+		Retries:  ctx.Int(retriesFlag),
+		Parallel: ctx.Int(parallelFlag),
+	}
+	if err = saveTaskState(homeDir, taskState); err != nil {
+		return toCmdErr(err)
+	}
+
+	return watchFolderByTask(ctx, homeDir, gnfdClient, taskState)
+}
+
+// findWatchTask return the watch task of the folder, the bucket and the prefix, or nil if there is no such task
+func findWatchTask(homeDir, folderName, bucketName, prefix string) (*TaskState, error) {
+	states, err := loadAllTaskStates(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		if state.GetKind() == TaskKindWatch && state.FolderName == folderName &&
+			state.BucketName == bucketName && state.Prefix == prefix {
+			return state, nil
+		}
+	}
+	return nil, nil
+}
+
+// folderWatcher scan the folder of the watch task and upload the settled files by the workers
+type folderWatcher struct {
+	gnfdClient client.IClient
+	taskState  *TaskState
+	filter     *objectFilter
+	// objectIndex is the index of the task objects by the object name
+	objectIndex map[string]int
+	// pending is the files which are not settled yet
+	pending map[string]pendingFile
+	// queued is the objects waiting for the workers or being uploaded
+	queued   map[int]bool
+	queuedMu sync.Mutex
+	queue    chan int
+}
+
+// pendingFile is the last observation of a file which is not settled yet
+type pendingFile struct {
+	size    int64
+	modTime time.Time
+	// since is the time when the file is observed unchanged for the first time
+	since time.Time
+}
+
+// watchFolderByTask run the watch task until the command is interrupted
+func watchFolderByTask(ctx *cli.Context, homeDir string, gnfdClient client.IClient, taskState *TaskState) error {
+	finishTask, err := startTask(homeDir, taskState)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	defer finishTask()
+
+	filter, err := newObjectFilterByFlag(taskState.FilterFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	signalCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	syncCtx, cancelSync := context.WithCancel(context.Background())
+	syncDone := make(chan struct{})
+	go func() {
+		stateSync(syncCtx, homeDir, taskState)
+		close(syncDone)
+	}()
+
+	watcher := &folderWatcher{
+		gnfdClient:  gnfdClient,
+		taskState:   taskState,
+		filter:      filter,
+		objectIndex: make(map[string]int),
+		pending:     make(map[string]pendingFile),
+		queued:      make(map[int]bool),
+		queue:       make(chan int),
+	}
+	for index, object := range taskState.ObjectState {
+		watcher.objectIndex[object.ObjectName] = index
+	}
+
+	parallel := taskState.WatchFlag.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+	var workers sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for index := range watcher.queue {
+				watcher.upload(index)
+			}
+		}()
+	}
+
+	fmt.Printf("watching %s, the new files are uploaded to %s%s/%s\n", taskState.FolderName, urlPrefix, taskState.BucketName, taskState.Prefix)
+	tick := time.NewTicker(taskState.WatchFlag.Interval)
+	defer tick.Stop()
+	for signalCtx.Err() == nil {
+		watcher.scan(signalCtx)
+		watcher.checkSealed(signalCtx)
+
+		select {
+		case <-tick.C:
+		case <-signalCtx.Done():
+		}
+	}
+
+	// the second interruption exits immediately
+	stop()
+	fmt.Println("\nstopping the watch, waiting for the running uploads...")
+	close(watcher.queue)
+	workers.Wait()
+
+	cancelSync()
+	<-syncDone
+	if err = saveTaskState(homeDir, taskState); err != nil {
+		return toCmdErr(err)
+	}
+	emitTaskDone(taskState)
+	fmt.Printf("the watch task %s is stopped: %s\n", taskState.TaskID, formatObjectStatusCounts(taskState))
+	return nil
+}
+
+// scan walk the folder, add the settled new files to the task and enqueue the objects to be uploaded
+func (w *folderWatcher) scan(ctx context.Context) {
+	now := time.Now()
+	seen := make(map[string]bool)
+	err := filepath.Walk(w.taskState.FolderName, func(path string, info os.FileInfo, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// the file may be removed while walking
+		if err != nil {
+			return nil
+		}
+		relPath, err := filepath.Rel(w.taskState.FolderName, path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+		if info.IsDir() {
+			if relPath != "." && w.filter.excluded(relPath, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || !w.filter.match(relPath, false) {
+			return nil
+		}
+
+		objectName := w.taskState.Prefix + relPath
+		if index, ok := w.objectIndex[objectName]; ok {
+			w.enqueue(ctx, index)
+			return nil
+		}
+
+		seen[path] = true
+		last, ok := w.pending[path]
+		if !ok || last.size != info.Size() || !last.modTime.Equal(info.ModTime()) {
+			w.pending[path] = pendingFile{size: info.Size(), modTime: info.ModTime(), since: now}
+			return nil
+		}
+		if now.Sub(last.since) < w.taskState.WatchFlag.Settle {
+			return nil
+		}
+
+		delete(w.pending, path)
+		index := w.taskState.AddObject(&UploadTaskObject{
+			BucketName: w.taskState.BucketName,
+			ObjectName: objectName,
+			FilePath:   path,
+			ObjectSize: info.Size(),
+			Status:     TaskObjectStatusWaitForUpload,
+			ModTime:    info.ModTime().UnixNano(),
+		})
+		w.objectIndex[objectName] = index
+		fmt.Printf("\radded %s\n", objectName)
+		w.enqueue(ctx, index)
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Printf("failed to scan the folder: %v\n", err)
+	}
+
+	// forget the pending files which are removed before they are settled
+	for path := range w.pending {
+		if !seen[path] {
+			delete(w.pending, path)
+		}
+	}
+}
+
+// object return the task object, the objects are added by the scan while the workers are running
+func (w *folderWatcher) object(index int) *UploadTaskObject {
+	w.taskState.Lock.Lock()
+	defer w.taskState.Lock.Unlock()
+	return w.taskState.ObjectState[index]
+}
+
+// enqueue hand the object to the workers if it waits for uploading or it failed with retries left,
+// it blocks until a worker is free or the watch is stopped
+func (w *folderWatcher) enqueue(ctx context.Context, index int) {
+	w.taskState.Lock.Lock()
+	object := w.taskState.ObjectState[index]
+	status, retryCount := object.Status, object.RetryCount
+	w.taskState.Lock.Unlock()
+
+	switch {
+	case status == TaskObjectStatusWaitForUpload:
+	case status == TaskObjectStatusFailed && retryCount < w.taskState.WatchFlag.Retries:
+	default:
+		return
+	}
+
+	w.queuedMu.Lock()
+	if w.queued[index] {
+		w.queuedMu.Unlock()
+		return
+	}
+	w.queued[index] = true
+	w.queuedMu.Unlock()
+
+	select {
+	case w.queue <- index:
+	case <-ctx.Done():
+		w.queuedMu.Lock()
+		delete(w.queued, index)
+		w.queuedMu.Unlock()
+	}
+}
+
+// upload create and upload the object by uploadFileByTask, the failed object is retried on the next scans
+func (w *folderWatcher) upload(index int) {
+	defer func() {
+		w.queuedMu.Lock()
+		delete(w.queued, index)
+		w.queuedMu.Unlock()
+	}()
+
+	taskState := w.taskState
+	object := w.object(index)
+	taskState.StartObject(index)
+	txnHash, err := uploadFileByTask(taskState.TaskID, object.BucketName, object.ObjectName, object.FilePath, taskState.Flag, w.gnfdClient, false, object.ObjectSize,
+		silentProgressBar(taskState.WatchFlag.Parallel))
+	if err != nil {
+		taskState.FinishObject(index, txnHash, 0)
+		taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
+		emitObjectEvent(progressEventFailed, taskState.TaskID, object.BucketName, object.ObjectName, err)
+		fmt.Printf("\r%s %s %s\n", TaskObjectStatusFailed, object.ObjectName, err.Error())
+		return
+	}
+	taskState.FinishObject(index, txnHash, object.ObjectSize)
+	taskState.UpdateObjectState(index, TaskObjectStatusCreated, "")
+	fmt.Printf("\r%s %s\n", TaskObjectStatusCreated, object.ObjectName)
+}
+
+// checkSealed update the status of the created objects which have been sealed
+func (w *folderWatcher) checkSealed(ctx context.Context) {
+	for index := 0; index < len(w.objectIndex); index++ {
+		if ctx.Err() != nil {
+			return
+		}
+		if w.taskState.GetObjectStatus(index) != TaskObjectStatusCreated {
+			continue
+		}
+		object := w.object(index)
+		headObjOutput, err := w.gnfdClient.HeadObject(ctx, object.BucketName, object.ObjectName)
+		if err != nil || headObjOutput.ObjectInfo.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+			continue
+		}
+		w.taskState.SealObject(index)
+		w.taskState.UpdateObjectState(index, TaskObjectStatusSeal, "")
+		emitObjectEvent(progressEventSealed, w.taskState.TaskID, object.BucketName, object.ObjectName, nil)
+		fmt.Printf("\r%s %s\n", TaskObjectStatusSeal, object.ObjectName)
+	}
+}
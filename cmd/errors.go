@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// the exit codes of the failed commands, the codes are stable so that the scripts can react to the kind of the failure
+const (
+	exitCodeGeneral             = 1
+	exitCodeInvalidArgs         = 2
+	exitCodeNotFound            = 3
+	exitCodeInsufficientBalance = 4
+	exitCodePermissionDenied    = 5
+	exitCodeTxFailed            = 6
+	exitCodeTxTimeout           = 7
+)
+
+// the kinds of the command errors, the returned errors can be checked by errors.Is
+var (
+	ErrInvalidArgs         = errors.New("invalid args")
+	ErrNotFound            = errors.New("not found")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrPermissionDenied    = errors.New("permission denied")
+	ErrTxFailed            = errors.New("transaction failed")
+	ErrTxTimeout           = errors.New("transaction timeout")
+)
+
+var errorKindExitCodes = []struct {
+	kind     error
+	exitCode int
+}{
+	{ErrInvalidArgs, exitCodeInvalidArgs},
+	{ErrNotFound, exitCodeNotFound},
+	{ErrInsufficientBalance, exitCodeInsufficientBalance},
+	{ErrPermissionDenied, exitCodePermissionDenied},
+	{ErrTxFailed, exitCodeTxFailed},
+	{ErrTxTimeout, exitCodeTxTimeout},
+}
+
+// cmdError is the error of the kind, the message of the wrapped error is kept
+type cmdError struct {
+	kind error
+	err  error
+}
+
+func newCmdError(kind, err error) error {
+	return &cmdError{kind: kind, err: err}
+}
+
+// invalidArgsError return the error of the invalid args or flags
+func invalidArgsError(format string, a ...interface{}) error {
+	return newCmdError(ErrInvalidArgs, fmt.Errorf(format, a...))
+}
+
+func (e *cmdError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cmdError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// classifyError return the kind of the error which is not typed, by the errors of the system and the messages
+// of the chain, the storage providers and the cli, or nil if the kind is unknown
+func classifyError(err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return ErrPermissionDenied
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	// the timeout of waiting for the txn is typed by waitTxnStatus, the other timeouts such as the requests
+	// to the storage providers are not classified
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, noBalanceErr), strings.Contains(msg, "insufficient fund"),
+		strings.Contains(msg, "insufficient fee"), strings.Contains(msg, "insufficient balance"):
+		return ErrInsufficientBalance
+	case strings.Contains(msg, "has failed with response code"), strings.Contains(msg, "execution reverted"):
+		return ErrTxFailed
+	case strings.Contains(msg, "has been submitted, please check it later"):
+		return ErrTxTimeout
+	case strings.Contains(msg, "permission denied"), strings.Contains(msg, "access denied"),
+		strings.Contains(msg, "no permission"), strings.Contains(msg, "unauthorized"):
+		return ErrPermissionDenied
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "not exist"):
+		return ErrNotFound
+	// the args number errors of the commands and the usage errors of the cli
+	case strings.Contains(msg, "args number"), strings.HasPrefix(msg, "required flag"),
+		strings.HasPrefix(msg, "flag provided but not defined"), strings.HasPrefix(msg, "invalid value"):
+		return ErrInvalidArgs
+	}
+	return nil
+}
+
+// exitCode return the exit code of the kind of the error
+func exitCode(err error) int {
+	for _, item := range errorKindExitCodes {
+		if errors.Is(err, item.kind) {
+			return item.exitCode
+		}
+	}
+	return exitCodeGeneral
+}
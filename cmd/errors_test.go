@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func Test_exitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: errors.New("unknown error"), want: exitCodeGeneral},
+		{err: fmt.Errorf("args number should be one"), want: exitCodeInvalidArgs},
+		{err: invalidArgsError("invalid amount %s", "x"), want: exitCodeInvalidArgs},
+		{err: errors.New(`Required flag "taskId" not set`), want: exitCodeInvalidArgs},
+		{err: ErrBucketNotExist, want: exitCodeNotFound},
+		{err: fmt.Errorf("failed to delete object a: %w", ErrObjectNotExist), want: exitCodeNotFound},
+		{err: os.ErrNotExist, want: exitCodeNotFound},
+		{err: errors.New("rpc error: key not found"), want: exitCodeInsufficientBalance},
+		{err: errors.New("insufficient funds: 1azkme is smaller than 2azkme"), want: exitCodeInsufficientBalance},
+		{err: os.ErrPermission, want: exitCodePermissionDenied},
+		{err: errors.New("AccessDenied: access denied"), want: exitCodePermissionDenied},
+		{err: errors.New("the createObject txn has failed with response code: 5"), want: exitCodeTxFailed},
+		{err: newCmdError(ErrTxTimeout, errors.New("the txn has been submitted")), want: exitCodeTxTimeout},
+		{err: newCmdError(ErrTxTimeout, context.DeadlineExceeded), want: exitCodeTxTimeout},
+		{err: fmt.Errorf("failed to put object: %w", context.DeadlineExceeded), want: exitCodeGeneral},
+		{err: errors.New("dial tcp: lookup sp.example.com: no such host"), want: exitCodeGeneral},
+		{err: fmt.Errorf("open a.txt: %w", os.ErrNotExist), want: exitCodeNotFound},
+	}
+	for _, tt := range tests {
+		err := toCmdErr(tt.err)
+		if got := exitCode(err); got != tt.want {
+			t.Errorf("exitCode(%q) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func Test_toCmdErr(t *testing.T) {
+	if toCmdErr(nil) != nil {
+		t.Error("toCmdErr(nil) should be nil")
+	}
+	err := toCmdErr(ErrBucketNotExist)
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrBucketNotExist) || err.Error() != "bucket not exist" {
+		t.Errorf("toCmdErr(ErrBucketNotExist) = %v, the kind and the message should be kept", err)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// defaultIgnoreFileName is the ignore file which is loaded from the root of the local folder when uploading
+const defaultIgnoreFileName = ".mechainignore"
+
+// globRule is a glob pattern of the include and exclude flags or a line of the ignore file
+type globRule struct {
+	pattern string
+	// negate indicates the pattern of the ignore file starts with "!", the matched path is included again
+	negate bool
+	// dirOnly indicates the pattern ends with "/", it only matches folders
+	dirOnly bool
+	// anchored indicates the pattern contains "/", it is matched against the whole relative path instead of the base name
+	anchored bool
+}
+
+// objectFilter decides which files or objects are selected by the include and exclude globs and the ignore file,
+// the paths passed to it are relative to the local folder or the prefix and separated by "/"
+type objectFilter struct {
+	includes []globRule
+	excludes []globRule
+	ignores  []globRule
+}
+
+// filterFlags return the flags which are shared by the commands supporting the include and exclude filters
+func filterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name: includeFlag,
+			Usage: "only handle the files or objects matching the glob pattern, the flag can be repeated. " +
+				"A pattern without \"/\" is matched against the base name, \"**\" matches any number of folders",
+		},
+		&cli.StringSliceFlag{
+			Name:  excludeFlag,
+			Usage: "skip the files or objects matching the glob pattern, the flag can be repeated and it takes precedence over --include",
+		},
+		&cli.StringFlag{
+			Name: ignoreFileFlag,
+			Usage: "the path of the ignore file in gitignore syntax, the " + defaultIgnoreFileName +
+				" file in the root of the local folder is used by default when uploading a folder",
+		},
+	}
+}
+
+// FilterFlag indicates the include and exclude globs and the ignore file, it is kept in the task state
+// so that the task can be re-planned with the same filter
+type FilterFlag struct {
+	Includes   []string `json:"includes,omitempty"`
+	Excludes   []string `json:"excludes,omitempty"`
+	IgnoreFile string   `json:"ignore_file,omitempty"`
+}
+
+// getFilterFlag parse the filter flags, the default ignore file is used if it exists and no ignore file is specified
+func getFilterFlag(ctx *cli.Context, defaultIgnoreFile string) FilterFlag {
+	filterFlag := FilterFlag{
+		Includes:   ctx.StringSlice(includeFlag),
+		Excludes:   ctx.StringSlice(excludeFlag),
+		IgnoreFile: ctx.String(ignoreFileFlag),
+	}
+	if filterFlag.IgnoreFile == "" && defaultIgnoreFile != "" && fileExists(defaultIgnoreFile) {
+		filterFlag.IgnoreFile = defaultIgnoreFile
+	}
+	return filterFlag
+}
+
+// newObjectFilter build the filter from the flags of the command
+func newObjectFilter(ctx *cli.Context, defaultIgnoreFile string) (*objectFilter, error) {
+	return newObjectFilterByFlag(getFilterFlag(ctx, defaultIgnoreFile))
+}
+
+// newObjectFilterByFlag build the filter from the globs and the ignore file
+func newObjectFilterByFlag(filterFlag FilterFlag) (*objectFilter, error) {
+	filter := &objectFilter{}
+	for _, pattern := range filterFlag.Includes {
+		rule, err := parseGlobRule(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.includes = append(filter.includes, rule)
+	}
+	for _, pattern := range filterFlag.Excludes {
+		rule, err := parseGlobRule(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.excludes = append(filter.excludes, rule)
+	}
+
+	if filterFlag.IgnoreFile != "" {
+		rules, err := loadIgnoreFile(filterFlag.IgnoreFile)
+		if err != nil {
+			return nil, err
+		}
+		filter.ignores = rules
+	}
+	return filter, nil
+}
+
+// loadIgnoreFile parse the ignore file in gitignore syntax
+func loadIgnoreFile(filePath string) ([]globRule, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rules := make([]globRule, 0)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := false
+		if strings.HasPrefix(line, "!") {
+			negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+
+		rule, err := parseGlobRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in %s line %d: %v", filePath, lineNum, err)
+		}
+		rule.negate = negate
+		rules = append(rules, rule)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func parseGlobRule(pattern string) (globRule, error) {
+	rule := globRule{}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if strings.Contains(pattern, "/") {
+		rule.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+	if pattern == "" {
+		return rule, fmt.Errorf("empty glob pattern")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return rule, fmt.Errorf("invalid glob pattern %s: %v", pattern, err)
+	}
+	rule.pattern = pattern
+	return rule, nil
+}
+
+// match report whether the relative path matches the rule
+func (r globRule) match(relPath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.anchored {
+		return matchGlob(r.pattern, relPath)
+	}
+	return matchGlob(r.pattern, path.Base(relPath))
+}
+
+// matchGlob report whether the name matches the pattern, both of them are separated by "/"
+// and "**" matches zero or more folders
+func matchGlob(pattern, name string) bool {
+	return matchGlobElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobElems(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			patterns = patterns[1:]
+			if len(patterns) == 0 {
+				return true
+			}
+			for i := 0; i <= len(names); i++ {
+				if matchGlobElems(patterns, names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, err := path.Match(patterns[0], names[0]); err != nil || !ok {
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// isEmpty report whether no filter rule is set
+func (f *objectFilter) isEmpty() bool {
+	return f == nil || len(f.includes) == 0 && len(f.excludes) == 0 && len(f.ignores) == 0
+}
+
+// match report whether the file or object of the relative path is selected, the path is skipped if itself or
+// any of its parent folders is excluded, and it is selected if no include pattern is set or itself or any of
+// its parent folders matches an include pattern
+func (f *objectFilter) match(relPath string, isDir bool) bool {
+	if f.isEmpty() {
+		return true
+	}
+	relPath = strings.Trim(relPath, "/")
+	if relPath == "" || relPath == "." {
+		return true
+	}
+	if f.excluded(relPath, isDir) {
+		return false
+	}
+	if len(f.includes) == 0 {
+		return true
+	}
+
+	elems := strings.Split(relPath, "/")
+	for i := 1; i <= len(elems); i++ {
+		subPath := strings.Join(elems[:i], "/")
+		for _, rule := range f.includes {
+			if rule.match(subPath, i < len(elems) || isDir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excluded report whether the relative path or any of its parent folders is excluded by the exclude flags or the ignore file,
+// the folder which is excluded can be skipped as a whole when walking the local folder
+func (f *objectFilter) excluded(relPath string, isDir bool) bool {
+	if f.isEmpty() {
+		return false
+	}
+	relPath = strings.Trim(relPath, "/")
+	if relPath == "" || relPath == "." {
+		return false
+	}
+
+	elems := strings.Split(relPath, "/")
+	for i := 1; i <= len(elems); i++ {
+		subPath := strings.Join(elems[:i], "/")
+		subIsDir := i < len(elems) || isDir
+		for _, rule := range f.excludes {
+			if rule.match(subPath, subIsDir) {
+				return true
+			}
+		}
+		// the last matched line of the ignore file decides whether the path is ignored
+		ignored := false
+		for _, rule := range f.ignores {
+			if rule.match(subPath, subIsDir) {
+				ignored = !rule.negate
+			}
+		}
+		if ignored {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeObjectName return the object name relative to the folder part of the prefix
+func relativeObjectName(prefixName, objectName string) string {
+	return strings.TrimPrefix(objectName, prefixName[:strings.LastIndex(prefixName, "/")+1])
+}
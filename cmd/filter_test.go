@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_matchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "*.go", name: "main.go", want: true},
+		{pattern: "*.go", name: "cmd/main.go", want: false},
+		{pattern: "cmd/*.go", name: "cmd/main.go", want: true},
+		{pattern: "**/*.go", name: "main.go", want: true},
+		{pattern: "**/*.go", name: "a/b/c/main.go", want: true},
+		{pattern: "build/**", name: "build/a/b", want: true},
+		{pattern: "a/**/b", name: "a/x/y/b", want: true},
+		{pattern: "a/**/b", name: "a/b", want: true},
+		{pattern: "a/**/b", name: "a/x/c", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_objectFilter_match(t *testing.T) {
+	ignoreFile := filepath.Join(t.TempDir(), defaultIgnoreFileName)
+	content := "# comment\n*.log\n!keep.log\nbuild/\n/tmp\n"
+	if err := os.WriteFile(ignoreFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ignores, err := loadIgnoreFile(ignoreFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	includeRule, _ := parseGlobRule("src")
+	excludeRule, _ := parseGlobRule(".git")
+	filter := &objectFilter{
+		includes: []globRule{includeRule},
+		excludes: []globRule{excludeRule},
+		ignores:  ignores,
+	}
+
+	tests := []struct {
+		relPath string
+		isDir   bool
+		want    bool
+	}{
+		{relPath: "src/main.go", want: true},
+		{relPath: "src", isDir: true, want: true},
+		{relPath: "doc/readme.md", want: false},
+		{relPath: "src/.git/config", want: false},
+		{relPath: "src/debug.log", want: false},
+		{relPath: "src/keep.log", want: true},
+		{relPath: "src/build/out.bin", want: false},
+		{relPath: "src/build", want: true},
+		{relPath: "src/tmp/a", want: true},
+	}
+	for _, tt := range tests {
+		if got := filter.match(tt.relPath, tt.isDir); got != tt.want {
+			t.Errorf("match(%q) = %v, want %v", tt.relPath, got, tt.want)
+		}
+	}
+
+	if !filter.excluded("tmp", true) {
+		t.Errorf("the anchored pattern should exclude the folder in the root")
+	}
+}
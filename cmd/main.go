@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 
@@ -67,6 +66,45 @@ func main() {
 			Usage: "directory for config and keystore",
 			Value: filepath.Join(homeDir, DefaultConfigDir),
 		},
+		&cli.StringFlag{
+			Name:    limitRateFlag,
+			Aliases: []string{"limit-rate"},
+			Usage:   "limit the total bandwidth of the uploads and the downloads respectively, such as 512K, 20M or 1G bytes per second",
+		},
+		&cli.StringFlag{
+			Name:  uploadLimitRateFlag,
+			Usage: "limit the total bandwidth of the uploads, it overrides --" + limitRateFlag,
+		},
+		&cli.StringFlag{
+			Name:  downloadLimitRateFlag,
+			Usage: "limit the total bandwidth of the downloads, it overrides --" + limitRateFlag,
+		},
+		&cli.GenericFlag{
+			Name: progressFlag,
+			Value: &CmdEnumValue{
+				Enum:    []string{progressModeBar, progressModePlain, progressModeJSON, progressModeNone},
+				Default: progressModeBar,
+			},
+			Usage: "the way of printing the transfer progress: the progress bar, plain lines for the logs, " +
+				"json events on stderr (started, progress, sealed, failed, done) or none",
+		},
+		&cli.GenericFlag{
+			Name:    outputFlag,
+			Aliases: []string{"o"},
+			Value: &CmdEnumValue{
+				Enum:    []string{outputTable, outputJSON, outputYAML},
+				Default: outputTable,
+			},
+			Usage: "the output format of the read commands such as ls, head, balance and task status: table, json or yaml",
+		},
+		&cli.StringFlag{
+			Name:  timeZoneFlag,
+			Usage: "the time zone of the shown times: local, UTC or a name such as America/New_York, the default is local",
+		},
+		&cli.StringFlag{
+			Name:  timeFormatFlag,
+			Usage: "the format of the shown times: datetime, rfc3339, unix or relative, the default is datetime",
+		},
 	}
 
 	app := &cli.App{
@@ -100,11 +138,17 @@ func main() {
 					cmdHeadObj(),
 					cmdCancelObjects(),
 					cmdListObjects(),
+					cmdDuObjects(),
+					cmdFindObjects(),
 					cmdCalHash(),
 					cmdUpdateObject(),
 					cmdGetUploadProgress(),
 					cmdMirrorObject(),
 					cmdSetTagForObject(),
+					cmdSyncObjects(),
+					cmdCopyObject(),
+					cmdMoveObject(),
+					cmdWatchObject(),
 				},
 			},
 			{
@@ -185,19 +229,42 @@ func main() {
 				Name:  "task",
 				Usage: "support the batch upload file",
 				Subcommands: []*cli.Command{
+					cmdTaskList(),
 					cmdTaskStatus(),
 					cmdTaskDelete(),
 					cmdTaskRetry(),
+					cmdTaskReport(),
+					cmdTaskPrune(),
 				},
 			},
 			cmdShowVersion(),
+			cmdCompletion(),
 		},
+		EnableBashCompletion: true,
+	}
+	setupCompletion(app.Commands, "")
+	initInputSource := altsrc.InitInputSourceWithContext(flags, altsrc.NewTomlSourceFromFlagFunc("config"))
+	app.Before = func(ctx *cli.Context) error {
+		if err := initInputSource(ctx); err != nil {
+			return err
+		}
+		progressMode = fmt.Sprintf("%s", ctx.Generic(progressFlag))
+		outputFormat = fmt.Sprintf("%s", ctx.Generic(outputFlag))
+		config, err := loadConfigIfExist(ctx)
+		if err != nil {
+			return err
+		}
+		if err = setupTimeFormat(ctx, config); err != nil {
+			return err
+		}
+		return setupRateLimiters(ctx, config)
 	}
-	app.Before = altsrc.InitInputSourceWithContext(flags, altsrc.NewTomlSourceFromFlagFunc("config"))
 
 	err = app.Run(os.Args)
 	if err != nil {
-		log.Fatal(err)
+		err = toCmdErr(err)
+		fmt.Fprintf(os.Stderr, "run command error: %s\n", err.Error())
+		os.Exit(exitCode(err))
 	}
 
 }
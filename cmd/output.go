@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/gogoproto/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// the output formats of the read commands, which are set by the global --output flag
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormat = outputTable
+
+// isStructuredOutput return true if the result should be serialized as json or yaml instead of the table
+func isStructuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputYAML
+}
+
+// printOutput serialize the result in the output format, the field names are the json tags of the output types
+func printOutput(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return printJSONOutput(data)
+}
+
+// printProtoOutput serialize the chain info in the output format, the field names are the original names of the proto fields
+func printProtoOutput(v proto.Message) error {
+	data, err := getJsonMarshaler().MarshalToString(v)
+	if err != nil {
+		return err
+	}
+	return printJSONOutput([]byte(data))
+}
+
+// printJSONOutput print the json data, it is converted to yaml in the yaml output format so that
+// both of the formats have the same field names
+func printJSONOutput(data []byte) error {
+	if outputFormat != outputYAML {
+		fmt.Println(string(data))
+		return nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+	out, err := yaml.Marshal(&node)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// resetYAMLStyle clear the flow style and the quotes of the json document, the strings which would be
+// read as other types are still quoted by the encoder
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// formatOutputTime format the unix seconds as RFC3339 in the time zone, or empty if the time is not set.
+// The time format is not applied, so the structured output keeps the stable format
+func formatOutputTime(unixSeconds int64) string {
+	if unixSeconds == 0 {
+		return ""
+	}
+	return time.Unix(unixSeconds, 0).In(displayTimeLocation).Format(time.RFC3339)
+}
+
+// objectOutput is an object of "object ls"
+type objectOutput struct {
+	ObjectName  string `json:"object_name"`
+	ObjectID    string `json:"object_id"`
+	Owner       string `json:"owner"`
+	Size        uint64 `json:"size"`
+	ContentType string `json:"content_type"`
+	Visibility  string `json:"visibility"`
+	Status      string `json:"status"`
+	CreateTime  string `json:"create_time"`
+	// Checksums is the hex checksums of the primary SP and the secondary SPs
+	Checksums []string          `json:"checksums"`
+	Tags      map[string]string `json:"tags"`
+	Removed   bool              `json:"removed"`
+}
+
+// objectListOutput is the result of "object ls", the prefixes are the folders of the non-recursive listing
+type objectListOutput struct {
+	Objects  []objectOutput `json:"objects"`
+	Prefixes []string       `json:"prefixes"`
+}
+
+// objectUsage is the number and the payload size of the objects of a prefix
+type objectUsage struct {
+	Prefix  string `json:"prefix"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+	// MonthlyCost is the estimated storage cost of a month in wei, it is empty if --cost is not set
+	MonthlyCost string `json:"monthly_cost,omitempty"`
+}
+
+// objectUsageOutput is the result of "object du"
+type objectUsageOutput struct {
+	Prefixes []objectUsage `json:"prefixes"`
+	Total    objectUsage   `json:"total"`
+	// StorePrice is the storage price of the primary SP in wei per byte per second
+	StorePrice string `json:"store_price,omitempty"`
+}
+
+// bucketOutput is a bucket of "bucket ls"
+type bucketOutput struct {
+	BucketName string `json:"bucket_name"`
+	BucketID   string `json:"bucket_id"`
+	Owner      string `json:"owner"`
+	Visibility string `json:"visibility"`
+	Status     string `json:"status"`
+	CreateTime string `json:"create_time"`
+}
+
+// groupOutput is a group of "group ls" and "group ls-belong"
+type groupOutput struct {
+	GroupName  string `json:"group_name"`
+	GroupID    string `json:"group_id"`
+	Owner      string `json:"owner"`
+	CreateTime string `json:"create_time"`
+}
+
+// groupMemberOutput is a member of "group ls-member"
+type groupMemberOutput struct {
+	Member     string `json:"member"`
+	CreateTime string `json:"create_time"`
+	ExpireTime string `json:"expire_time"`
+}
+
+// policyStatementOutput is a statement of "policy ls", the principal is the grantee address or the group id
+type policyStatementOutput struct {
+	Principal string   `json:"principal"`
+	Actions   []string `json:"actions"`
+	Effect    string   `json:"effect"`
+	Resource  string   `json:"resource"`
+}
+
+// spOutput is a storage provider of "sp ls"
+type spOutput struct {
+	Name            string `json:"name"`
+	OperatorAddress string `json:"operator_address"`
+	Endpoint        string `json:"endpoint"`
+	Status          string `json:"status"`
+}
+
+// paymentAccountOutput is a payment account of "payment-account ls"
+type paymentAccountOutput struct {
+	Address    string `json:"address"`
+	Owner      string `json:"owner"`
+	Refundable bool   `json:"refundable"`
+}
+
+// balanceOutput is the result of "bank balance", the balance is in the smallest unit of the denom
+type balanceOutput struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+	Denom   string `json:"denom"`
+}
+
+// taskOutput is a task of "task ls", the size is the disk usage of the task directory
+type taskOutput struct {
+	TaskID     string `json:"task_id"`
+	Kind       string `json:"kind"`
+	CreateTime string `json:"create_time"`
+	Status     string `json:"status"`
+	Size       int64  `json:"size"`
+	BucketName string `json:"bucket_name"`
+	Folder     string `json:"folder"`
+	// Objects is the number of the objects in each status of the task kind
+	Objects map[string]int `json:"objects"`
+}
+
+// taskStatusOutput is the result of "task status"
+type taskStatusOutput struct {
+	TaskID     string                   `json:"task_id"`
+	Kind       string                   `json:"kind"`
+	Status     string                   `json:"status"`
+	Folder     string                   `json:"folder"`
+	BucketName string                   `json:"bucket_name"`
+	Objects    []taskObjectStatusOutput `json:"objects"`
+}
+
+// taskObjectStatusOutput is an object of "task status"
+type taskObjectStatusOutput struct {
+	ObjectName string `json:"object_name"`
+	Status     string `json:"status"`
+	Comment    string `json:"comment"`
+}
+
+// groupMemberCheckOutput is the result of "group head-member"
+type groupMemberCheckOutput struct {
+	Member    string `json:"member"`
+	GroupName string `json:"group_name"`
+	IsMember  bool   `json:"is_member"`
+}
+
+// formatExpireTime format the expiration time of the group member which is in unix seconds
+func formatExpireTime(expirationTime string) string {
+	expireTime, err := strconv.ParseInt(expirationTime, 10, 64)
+	if err != nil {
+		return ""
+	}
+	return formatOutputTime(expireTime)
+}
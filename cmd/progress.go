@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the modes of printing the transfer progress, which are set by the global --progress flag
+const (
+	// progressModeBar print the progress in place of the terminal line
+	progressModeBar = "bar"
+	// progressModePlain print the progress line by line for the logs
+	progressModePlain = "plain"
+	// progressModeJSON emit the progress events as json lines to stderr
+	progressModeJSON = "json"
+	// progressModeNone print no progress
+	progressModeNone = "none"
+)
+
+// the events of the json progress mode
+const (
+	progressEventStarted  = "started"
+	progressEventProgress = "progress"
+	progressEventSealed   = "sealed"
+	progressEventFailed   = "failed"
+	// progressEventDone indicates the payload of the object is transferred, or the task is finished if no object is set
+	progressEventDone = "done"
+)
+
+var (
+	progressMode = progressModeBar
+	// progressMu keeps the json events of the concurrent transfers from interleaving
+	progressMu sync.Mutex
+)
+
+// progressEvent is a line of the json progress, the rate is in bytes per second
+type progressEvent struct {
+	Event  string  `json:"event"`
+	Time   string  `json:"time"`
+	TaskID string  `json:"task_id,omitempty"`
+	Bucket string  `json:"bucket,omitempty"`
+	Object string  `json:"object,omitempty"`
+	Bytes  int64   `json:"bytes,omitempty"`
+	Total  int64   `json:"total,omitempty"`
+	Rate   float64 `json:"rate,omitempty"`
+	Status string  `json:"status,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// emitProgressEvent write the event as a json line to stderr in the json progress mode,
+// stderr is used so that the events do not mix with the payload or the other output on stdout
+func emitProgressEvent(event progressEvent) {
+	if progressMode != progressModeJSON {
+		return
+	}
+	event.Time = time.Now().Format(time.RFC3339Nano)
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	_, _ = os.Stderr.Write(append(line, '\n'))
+}
+
+// emitObjectEvent emit the event of the object without the transfer info
+func emitObjectEvent(event, taskID, bucketName, objectName string, err error) {
+	progressEvent := progressEvent{Event: event, TaskID: taskID, Bucket: bucketName, Object: objectName}
+	if err != nil {
+		progressEvent.Error = err.Error()
+	}
+	emitProgressEvent(progressEvent)
+}
+
+// emitTaskDone emit the done event of the task with its final status
+func emitTaskDone(taskState *TaskState) {
+	emitProgressEvent(progressEvent{Event: progressEventDone, TaskID: taskState.TaskID, Bucket: taskState.BucketName, Status: taskState.Status})
+}
+
+// printTransferProgress print the progress of the payload transfer in the progress mode, the action is "uploading" or "downloading",
+// and the object event identifies the task, the bucket and the object of the json events.
+// The bar and plain progress is not printed if silent is true, and the printed string of the bar mode is returned
+// so that it can be cleared by the next printing
+func printTransferProgress(out io.Writer, action string, object progressEvent, current, total int64, startTime time.Time,
+	lastPrintedStr string, silent bool,
+) string {
+	rate := float64(current) / time.Since(startTime).Seconds()
+	switch progressMode {
+	case progressModeJSON:
+		object.Event, object.Bytes, object.Total, object.Rate = progressEventProgress, current, total, rate
+		emitProgressEvent(object)
+	case progressModePlain:
+		if !silent {
+			fmt.Fprintf(out, "%s %s\n", object.Object, formatTransferProgress(action, current, total, rate))
+		}
+	case progressModeBar:
+		if !silent {
+			progressStr := formatTransferProgress(action, current, total, rate)
+			// clear the last printed progress since the new one may be shorter
+			fmt.Fprint(out, "\r", strings.Repeat(" ", len(lastPrintedStr)), "\r", progressStr)
+			return progressStr
+		}
+	}
+	return lastPrintedStr
+}
+
+// silentProgressBar report whether the progress of the transfers running in parallel should not be printed, since their
+// bars would overwrite each other in the terminal line. The plain lines and the json events are still printed
+func silentProgressBar(parallel int) bool {
+	return parallel > 1 && progressMode == progressModeBar
+}
+
+func formatTransferProgress(action string, current, total int64, rate float64) string {
+	var progress float64 = 100
+	if total > 0 {
+		progress = float64(current) / float64(total) * 100
+	}
+	return fmt.Sprintf("%s progress: %.2f%% [ %s / %s ], rate: %s", action, progress,
+		getConvertSize(current), getConvertSize(total), getConvertRate(rate))
+}
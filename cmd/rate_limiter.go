@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// uploadLimiter and downloadLimiter limit the total throughput of the uploads and the downloads in the process,
+// they are shared by all the concurrent transfers. The nil limiter means no limit
+var (
+	uploadLimiter   *RateLimiter
+	downloadLimiter *RateLimiter
+)
+
+// RateLimiter is a token bucket limiting the bytes transferred per second
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter return the limiter of the bytes per second, the burst allows the transfer of one second at most
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	return &RateLimiter{
+		rate:   float64(bytesPerSecond),
+		burst:  float64(bytesPerSecond),
+		tokens: float64(bytesPerSecond),
+		last:   time.Now(),
+	}
+}
+
+// Wait block until the n bytes are allowed to be transferred. The bytes are reserved before sleeping,
+// so the concurrent transfers share the rate fairly
+func (l *RateLimiter) Wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// checkResumableDownloadLimit return an error if the resumable download is run with a download limit, since the resumable
+// downloads are written to the file by the SDK directly and can't be limited
+func checkResumableDownloadLimit(resumable bool) error {
+	if resumable && downloadLimiter != nil {
+		return invalidArgsError("the resumable download can't be limited, unset --%s or the download limit", resumableFlag)
+	}
+	return nil
+}
+
+// parseRate parse the rate such as 512K, 20M or 1G in bytes per second, the units are multiples of 1024,
+// and the optional "B" or "B/s" suffix is allowed. The empty value or 0 means no limit
+func parseRate(value string) (int64, error) {
+	rate, err := parseSize(strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(value)), "/S"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %s, it should be like 512K, 20M or 1G", value)
+	}
+	return rate, nil
+}
+
+// setupRateLimiters create the limiters from the global flags, the limits in the config file are used if the flags are not set.
+// The --limitRate applies to both of the uploads and the downloads, and it is overridden by the limit of one direction
+func setupRateLimiters(ctx *cli.Context, config *cmdConfig) error {
+	limitRate, uploadRate, downloadRate := ctx.String(limitRateFlag), ctx.String(uploadLimitRateFlag), ctx.String(downloadLimitRateFlag)
+	if limitRate == "" && uploadRate == "" && downloadRate == "" && config != nil {
+		limitRate, uploadRate, downloadRate = config.LimitRate, config.UploadLimitRate, config.DownloadLimitRate
+	}
+	if uploadRate == "" {
+		uploadRate = limitRate
+	}
+	if downloadRate == "" {
+		downloadRate = limitRate
+	}
+
+	upload, err := parseRate(uploadRate)
+	if err != nil {
+		return err
+	}
+	download, err := parseRate(downloadRate)
+	if err != nil {
+		return err
+	}
+	if upload > 0 {
+		uploadLimiter = NewRateLimiter(upload)
+	}
+	if download > 0 {
+		downloadLimiter = NewRateLimiter(download)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseRate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "0", want: 0},
+		{value: "1024", want: 1024},
+		{value: "512K", want: 512 * 1024},
+		{value: "20M", want: 20 * 1024 * 1024},
+		{value: "1G", want: 1024 * 1024 * 1024},
+		{value: "20m", want: 20 * 1024 * 1024},
+		{value: "20MB", want: 20 * 1024 * 1024},
+		{value: "20MB/s", want: 20 * 1024 * 1024},
+		{value: " 20M/s ", want: 20 * 1024 * 1024},
+		{value: "fast", wantErr: true},
+		{value: "-1M", wantErr: true},
+		{value: "20X", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRate(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseRate(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	// the nil limiter means no limit
+	var unlimited *RateLimiter
+	start := time.Now()
+	unlimited.Wait(1 << 30)
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Wait() of the nil limiter took %v", elapsed)
+	}
+
+	limiter := NewRateLimiter(1000)
+	// the burst of one second is allowed without waiting
+	start = time.Now()
+	limiter.Wait(1000)
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Wait() within the burst took %v", elapsed)
+	}
+
+	// the bucket is empty, so 500 bytes take about half a second
+	start = time.Now()
+	limiter.Wait(500)
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Wait() of 500 bytes at 1000 bytes per second took %v, want about 500ms", elapsed)
+	}
+
+	// the bytes of the concurrent transfers are reserved in turn, so the second one waits for both of them
+	done := make(chan time.Duration, 2)
+	start = time.Now()
+	for i := 0; i < 2; i++ {
+		go func() {
+			limiter.Wait(250)
+			done <- time.Since(start)
+		}()
+	}
+	longest := max(<-done, <-done)
+	if longest < 400*time.Millisecond || longest > time.Second {
+		t.Errorf("Wait() of 2 x 250 bytes at 1000 bytes per second took %v, want about 500ms", longest)
+	}
+}
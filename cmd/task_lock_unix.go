@@ -0,0 +1,43 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockTaskDir take the exclusive lock of the task directory, so that a task can only be run by one process at the same time.
+// The lock is released by the returned function, or by the system if the process exits
+func lockTaskDir(homeDir, taskID string) (func(), error) {
+	lockPath := filepath.Join(filepath.Dir(getTaskFilePath(homeDir, taskID)), "lock")
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		return nil, err
+	}
+	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		lockFile.Close()
+		return nil, fmt.Errorf("the task %s is being run by another process", taskID)
+	}
+	return func() {
+		_ = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+		lockFile.Close()
+	}, nil
+}
+
+// removeTaskDir remove the directory of the task while holding its lock, so that the task can't be started
+// before the directory is removed. It fails if the task is being run
+func removeTaskDir(homeDir, taskID string) error {
+	unlock, err := lockTaskDir(homeDir, taskID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// the locked file can be removed, the lock is held until the file is closed
+	return os.RemoveAll(filepath.Dir(getTaskFilePath(homeDir, taskID)))
+}
@@ -0,0 +1,54 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockTaskDir take the exclusive lock of the task directory by creating the lock file, so that a task can only be run
+// by one process at the same time. The lock is released by the returned function, the lock file needs to be removed
+// manually if the process exits unexpectedly
+func lockTaskDir(homeDir, taskID string) (func(), error) {
+	lockPath := filepath.Join(filepath.Dir(getTaskFilePath(homeDir, taskID)), "lock")
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		return nil, err
+	}
+	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("the task %s is being run by another process, remove %s if it is not", taskID, lockPath)
+	}
+	lockFile.Close()
+	return func() {
+		_ = os.Remove(lockPath)
+	}, nil
+}
+
+// removeTaskDir remove the directory of the task while holding its lock, so that the task can't be started
+// before the directory is removed. It fails if the task is being run
+func removeTaskDir(homeDir, taskID string) error {
+	unlock, err := lockTaskDir(homeDir, taskID)
+	if err != nil {
+		return err
+	}
+	taskDir := filepath.Dir(getTaskFilePath(homeDir, taskID))
+	entries, err := os.ReadDir(taskDir)
+	if err != nil {
+		unlock()
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == "lock" {
+			continue
+		}
+		if err = os.RemoveAll(filepath.Join(taskDir, entry.Name())); err != nil {
+			unlock()
+			return err
+		}
+	}
+	// the lock file is removed last, so that the task can't be started before its files are removed
+	unlock()
+	return os.Remove(taskDir)
+}
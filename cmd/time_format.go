@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// the formats of the times shown by the commands, which are set by the global --timeFormat flag
+const (
+	// timeFormatDatetime is the date and the time without the zone, such as 2024-12-12 10:00:00
+	timeFormatDatetime = "datetime"
+	timeFormatRFC3339  = "rfc3339"
+	// timeFormatUnix is the unix timestamp in seconds
+	timeFormatUnix = "unix"
+	// timeFormatRelative is the duration from now, such as "3 hours ago" or "in 2 days"
+	timeFormatRelative = "relative"
+
+	timeZoneLocal = "local"
+	timeZoneUTC   = "UTC"
+)
+
+var (
+	displayTimeLocation = time.Local
+	displayTimeFormat   = timeFormatDatetime
+)
+
+// setupTimeFormat set the time zone and the time format from the global flags, the settings in the config file
+// are used if the flags are not set
+func setupTimeFormat(ctx *cli.Context, config *cmdConfig) error {
+	zone, format := ctx.String(timeZoneFlag), ctx.String(timeFormatFlag)
+	if config != nil {
+		if zone == "" {
+			zone = config.TimeZone
+		}
+		if format == "" {
+			format = config.TimeFormat
+		}
+	}
+
+	location, err := parseTimeZone(zone)
+	if err != nil {
+		return err
+	}
+	displayTimeLocation = location
+
+	switch format = strings.ToLower(format); format {
+	case "":
+	case timeFormatDatetime, timeFormatRFC3339, timeFormatUnix, timeFormatRelative:
+		displayTimeFormat = format
+	default:
+		return invalidArgsError("invalid time format %s, it should be one of %s, %s, %s and %s", format,
+			timeFormatDatetime, timeFormatRFC3339, timeFormatUnix, timeFormatRelative)
+	}
+	return nil
+}
+
+// parseTimeZone return the location of the time zone, which is "local", "UTC" or a name of the IANA time zone database
+// such as "America/New_York"
+func parseTimeZone(zone string) (*time.Location, error) {
+	switch {
+	case zone == "" || strings.EqualFold(zone, timeZoneLocal):
+		return time.Local, nil
+	case strings.EqualFold(zone, timeZoneUTC):
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, invalidArgsError("invalid time zone %s: %v", zone, err)
+	}
+	return location, nil
+}
+
+// formatTime format the time in the time zone and the time format. The time is always RFC3339 in the json
+// and yaml output, so that the output can be parsed by scripts whatever the time format is
+func formatTime(t time.Time) string {
+	t = t.In(displayTimeLocation)
+	if isStructuredOutput() {
+		return t.Format(time.RFC3339)
+	}
+	switch displayTimeFormat {
+	case timeFormatRFC3339:
+		return t.Format(time.RFC3339)
+	case timeFormatUnix:
+		return fmt.Sprintf("%d", t.Unix())
+	case timeFormatRelative:
+		return formatRelativeTime(t, time.Now())
+	}
+	return t.Format(iso8601DateFormat)
+}
+
+// formatUnixTime format the unix seconds by formatTime
+func formatUnixTime(unixSeconds int64) string {
+	return formatTime(time.Unix(unixSeconds, 0))
+}
+
+// timeColumnWidth return the width of the time column of the tables
+func timeColumnWidth() int {
+	switch displayTimeFormat {
+	case timeFormatRFC3339:
+		return len(time.RFC3339)
+	case timeFormatUnix:
+		return len("1700000000")
+	case timeFormatRelative:
+		return len("11 months ago")
+	}
+	return len(iso8601DateFormat)
+}
+
+// formatRelativeTime return the duration between the time and now in the largest unit
+func formatRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	var relative string
+	for _, unit := range units {
+		if n := int64(d / unit.duration); n > 0 {
+			relative = fmt.Sprintf("%d %s", n, unit.name)
+			if n > 1 {
+				relative += "s"
+			}
+			break
+		}
+	}
+	if future {
+		return "in " + relative
+	}
+	return relative + " ago"
+}
+
+// parseTimeBound parse the time of the time range flags, which is a time in RFC3339, "2006-01-02 15:04:05" or
+// "2006-01-02" in the time zone, the unix seconds, or the duration before now such as 36h or 7d
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if duration, err := parseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{iso8601DateFormat, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, displayTimeLocation); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, invalidArgsError("invalid time %s, it should be like 2024-03-01, \"2024-03-01 10:00:00\", "+
+		"2024-03-01T10:00:00Z, the unix seconds or the duration before now such as 7d", value)
+}
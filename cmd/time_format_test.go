@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_formatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 12, 12, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		time time.Time
+		want string
+	}{
+		{time: now.Add(-30 * time.Second), want: "just now"},
+		{time: now.Add(-time.Minute), want: "1 minute ago"},
+		{time: now.Add(-3 * time.Hour), want: "3 hours ago"},
+		{time: now.Add(49 * time.Hour), want: "in 2 days"},
+		{time: now.AddDate(0, 0, -45), want: "1 month ago"},
+		{time: now.AddDate(-2, 0, -1), want: "2 years ago"},
+	}
+	for _, tt := range tests {
+		if got := formatRelativeTime(tt.time, now); got != tt.want {
+			t.Errorf("formatRelativeTime(%s) = %q, want %q", tt.time, got, tt.want)
+		}
+	}
+}
+
+func Test_parseTimeZone(t *testing.T) {
+	if location, err := parseTimeZone(""); err != nil || location != time.Local {
+		t.Errorf("the default time zone should be local, got %v %v", location, err)
+	}
+	if location, err := parseTimeZone("utc"); err != nil || location != time.UTC {
+		t.Errorf("parseTimeZone(utc) = %v %v, want UTC", location, err)
+	}
+	if _, err := parseTimeZone("Not/AZone"); err == nil {
+		t.Error("parseTimeZone should fail with the unknown time zone")
+	}
+}
+
+func Test_parseTimeBound(t *testing.T) {
+	displayTimeLocation = time.UTC
+	defer func() { displayTimeLocation = time.Local }()
+
+	now := time.Date(2024, 12, 12, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "36h", want: now.Add(-36 * time.Hour)},
+		{value: "1700000000", want: time.Unix(1700000000, 0)},
+		{value: "2024-03-01", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-03-01 10:30:00", want: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{value: "2024-03-01T10:30:00+08:00", want: time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeBound(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseTimeBound(%q) = %v %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := parseTimeBound("March", now); err == nil {
+		t.Error("parseTimeBound should fail with the invalid time")
+	}
+}
@@ -69,6 +69,44 @@ const (
 	IdFlag                  = "id"
 	DestChainIdFlag         = "destChainId"
 	taskIDFlag              = "taskId"
+	deleteFlag              = "delete"
+	parallelFlag            = "parallel"
+	sizeFlag                = "size"
+	includeFlag             = "include"
+	excludeFlag             = "exclude"
+	ignoreFileFlag          = "ignoreFile"
+	dryRunFlag              = "dryRun"
+	yesFlag                 = "yes"
+	taskStatusFlag          = "status"
+	recordHashFlag          = "recordHash"
+	onChangeFlag            = "onChange"
+	olderThanFlag           = "olderThan"
+	keepLastFlag            = "keepLast"
+	settleFlag              = "settle"
+	intervalFlag            = "interval"
+	retriesFlag             = "retries"
+	limitRateFlag           = "limitRate"
+	uploadLimitRateFlag     = "uploadLimitRate"
+	downloadLimitRateFlag   = "downloadLimitRate"
+	progressFlag            = "progress"
+	outputFlag              = "output"
+	timeZoneFlag            = "tz"
+	timeFormatFlag          = "timeFormat"
+	longFlag                = "long"
+	humanReadableFlag       = "humanReadable"
+	showRemovedFlag         = "showRemoved"
+	sortByFlag              = "sortBy"
+	depthFlag               = "depth"
+	costFlag                = "cost"
+	nameRegexFlag           = "name"
+	minSizeFlag             = "minSize"
+	maxSizeFlag             = "maxSize"
+	createdAfterFlag        = "createdAfter"
+	createdBeforeFlag       = "createdBefore"
+	objectStatusFlag        = "status"
+	tagFilterFlag           = "tag"
+	setTagsFlag             = "setTags"
+	setVisibilityFlag       = "setVisibility"
 
 	ownerAddressFlag = "owner"
 	addressFlag      = "address"
@@ -111,8 +149,9 @@ const (
 	evmRpcAddrConfigField = "evmRpcAddr"
 
 	// resumable download & upload
-	partSizeFlag  = "partSize"
-	resumableFlag = "resumable"
+	partSizeFlag   = "partSize"
+	resumableFlag  = "resumable"
+	skipVerifyFlag = "skipVerify"
 
 	// download with specified sp endpoint
 	spEndpointFlag = "spEndpoint"
@@ -122,6 +161,18 @@ const (
 	exitStatus         = "GRACEFUL_EXITING"
 	StatusSPrefix      = "STATUS_"
 	defaultMaxKey      = 500
+	defaultParallelNum = 4
+	onChangeAbort      = "abort"
+	onChangeReplan     = "replan"
+	objectSortByName   = "name"
+	objectSortBySize   = "size"
+	objectSortByTime   = "time"
+	// the operations touching more resources than the threshold need to be confirmed
+	confirmThreshold = 100
+	// the object statuses of the --status flag of "object find"
+	objectStatusCreated      = "created"
+	objectStatusSealed       = "sealed"
+	objectStatusDiscontinued = "discontinued"
 
 	noBalanceErr           = "key not found"
 	maxListMemberNum       = 1000
@@ -131,24 +182,40 @@ const (
 	printRateInterval  = time.Second / 2
 	bytesToReadForMIME = 512
 	notFound           = -1
+	stdioPath          = "-"
 
 	TaskStatusCreate  = "created"
 	TaskStatusFail    = "failed"
 	TaskStatusSuccess = "successful"
 
-	TaskObjectStatusWaitForUpload = "wait_for_upload"
-	TaskObjectStatusCreated       = "created"
-	TaskObjectStatusSeal          = "sealed"
-	TaskObjectStatusFailed        = "failed"
+	TaskKindUpload   = "upload"
+	TaskKindDownload = "download"
+	TaskKindDelete   = "delete"
+	TaskKindWatch    = "watch"
+
+	TaskObjectStatusWaitForUpload   = "wait_for_upload"
+	TaskObjectStatusCreated         = "created"
+	TaskObjectStatusSeal            = "sealed"
+	TaskObjectStatusWaitForDownload = "wait_for_download"
+	TaskObjectStatusDownloaded      = "downloaded"
+	TaskObjectStatusWaitForDelete   = "wait_for_delete"
+	TaskObjectStatusDeleted         = "deleted"
+	TaskObjectStatusSkipped         = "skipped"
+	TaskObjectStatusFailed          = "failed"
+
+	transferStatusSuccess = "succeeded"
+	transferStatusSkipped = "skipped"
+	transferStatusFailed  = "failed"
 )
 
 var (
-	ErrBucketNotExist     = errors.New("bucket not exist")
-	ErrObjectNotExist     = errors.New("object not exist")
-	ErrObjectNotCreated   = errors.New("object not created on chain")
+	ErrBucketNotExist     = newCmdError(ErrNotFound, errors.New("bucket not exist"))
+	ErrObjectNotExist     = newCmdError(ErrNotFound, errors.New("object not exist"))
+	ErrObjectNotCreated   = newCmdError(ErrNotFound, errors.New("object not created on chain"))
 	ErrObjectSeal         = errors.New("object not sealed before downloading")
-	ErrGroupNotExist      = errors.New("group not exist")
-	ErrFileNotExist       = errors.New("file path not exist")
+	ErrGroupNotExist      = newCmdError(ErrNotFound, errors.New("group not exist"))
+	ErrFileNotExist       = newCmdError(ErrNotFound, errors.New("file path not exist"))
+	ErrChecksumMismatch   = errors.New("the checksum of the downloaded file does not match the object on chain")
 	SyncBroadcastMode     = tx.BroadcastMode_BROADCAST_MODE_SYNC
 	TxnOptionWithSyncMode = types.TxOption{Mode: &SyncBroadcastMode}
 )
@@ -186,6 +253,20 @@ func (e CmdEnumValue) String() string {
 	return e.selected
 }
 
+// getVisibilityName return the name of the visibility which is the same as the value of the --visibility flag
+func getVisibilityName(visibility storageTypes.VisibilityType) string {
+	switch visibility {
+	case storageTypes.VISIBILITY_TYPE_PUBLIC_READ:
+		return publicReadType
+	case storageTypes.VISIBILITY_TYPE_PRIVATE:
+		return privateType
+	case storageTypes.VISIBILITY_TYPE_INHERIT:
+		return inheritType
+	default:
+		return strings.ToLower(strings.TrimPrefix(visibility.String(), "VISIBILITY_TYPE_"))
+	}
+}
+
 func getVisibilityType(visibility string) (storageTypes.VisibilityType, error) {
 	switch visibility {
 	case publicReadType:
@@ -199,13 +280,20 @@ func getVisibilityType(visibility string) (storageTypes.VisibilityType, error) {
 	}
 }
 
+// toCmdErr return the typed error of the failed command, the kind of the error decides the exit code
 func toCmdErr(err error) error {
+	var cmdErr *cmdError
+	if err == nil || errors.As(err, &cmdErr) {
+		return err
+	}
+	kind := classifyError(err)
+	if kind == nil {
+		return err
+	}
 	if strings.Contains(err.Error(), noBalanceErr) {
-		fmt.Println("The operator account have no balance, please transfer token to your account")
-	} else {
-		fmt.Printf("run command error: %s\n", err.Error())
+		err = errors.New("the operator account have no balance, please transfer token to your account")
 	}
-	return nil
+	return newCmdError(kind, err)
 }
 
 // parse object info meta on the chain
@@ -218,9 +306,7 @@ func parseObjectInfo(objectDetail *sdktypes.ObjectDetail) {
 		if strings.Contains(objInfo, "create_at:") {
 			timeInfo := strings.Split(objInfo, ":")
 			timestamp, _ := strconv.ParseInt(timeInfo[1], 10, 64)
-			location, _ := time.LoadLocation("Asia/Shanghai")
-			t := time.Unix(timestamp, 0).In(location)
-			objInfo = timeInfo[0] + ":" + t.Format(iso8601DateFormat)
+			objInfo = timeInfo[0] + ":" + formatUnixTime(timestamp)
 		}
 		if strings.Contains(objInfo, "checksums:") {
 			if checksumID == 0 {
@@ -245,13 +331,13 @@ func getJsonMarshaler() *jsonpb.Marshaler {
 	}
 }
 
-func parseByJsonFormat(v proto.Message) {
+func parseByJsonFormat(v proto.Message) error {
 	jsonData, err := getJsonMarshaler().MarshalToString(v)
 	if err != nil {
-		fmt.Println("Error marshalling to JSON:", err)
-		return
+		return fmt.Errorf("failed to marshal to json: %v", err)
 	}
 	fmt.Println(jsonData)
+	return nil
 }
 
 func parseBucketInfo(info *storageTypes.BucketInfo, gvgf *vgTypes.GlobalVirtualGroupFamily) {
@@ -261,9 +347,7 @@ func parseBucketInfo(info *storageTypes.BucketInfo, gvgf *vgTypes.GlobalVirtualG
 		if strings.Contains(bucketInfo, "create_at:") {
 			timeInfo := strings.Split(bucketInfo, ":")
 			timestamp, _ := strconv.ParseInt(timeInfo[1], 10, 64)
-			location, _ := time.LoadLocation("Asia/Shanghai")
-			t := time.Unix(timestamp, 0).In(location)
-			bucketInfo = timeInfo[0] + ":" + t.Format(iso8601DateFormat)
+			bucketInfo = timeInfo[0] + ":" + formatUnixTime(timestamp)
 		}
 		fmt.Println(bucketInfo)
 	}
@@ -415,6 +499,50 @@ func parseActions(ctx *cli.Context, resourceType ResourceType) ([]permTypes.Acti
 	return actions, isObjectActionInBucketPolicy, nil
 }
 
+// isObjectNotExistErr report whether the error indicates the object does not exist on chain
+func isObjectNotExistErr(err error) bool {
+	return err != nil && strings.Contains(err.Error(), storageTypes.ErrNoSuchObject.Error())
+}
+
+// dryRunCliFlag return the flag to show the resources which would be affected without changing them
+func dryRunCliFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    dryRunFlag,
+		Aliases: []string{"dry-run"},
+		Value:   false,
+		Usage:   "list the resources which would be affected with the counts and the total size, without changing them",
+	}
+}
+
+// yesCliFlag return the flag to skip the interactive confirmation
+func yesCliFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    yesFlag,
+		Aliases: []string{"y"},
+		Value:   false,
+		Usage:   "skip the interactive confirmation of the recursive deleting or the operation touching many resources",
+	}
+}
+
+// confirmOperation ask the user to confirm the operation, it returns true directly if the --yes flag is set.
+// The operation is refused if the stdin is not a terminal since nobody can answer the prompt
+func confirmOperation(ctx *cli.Context, operation string) (bool, error) {
+	if ctx.Bool(yesFlag) {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("the operation needs to be confirmed: %s, please set --%s to run it without a terminal", operation, yesFlag)
+	}
+
+	fmt.Fprintf(os.Stderr, "Are you sure to %s? [y/N]: ", operation)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
 // getPassword return the password content
 func getPassword(ctx *cli.Context, needNotice bool) (string, error) {
 	var filepath string
@@ -427,15 +555,15 @@ func getPassword(ctx *cli.Context, needNotice bool) (string, error) {
 		return strings.TrimRight(string(readContent), "\r\n"), nil
 	}
 
-	fmt.Print("Please enter the passphrase now:")
+	// the prompt is printed to stderr, so that it does not mix with the payload when stdout carries data
+	fmt.Fprint(os.Stderr, "Please enter the passphrase now:")
 
 	bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
 	if err != nil {
-		fmt.Println("read password err:", err)
-		return "", err
+		return "", fmt.Errorf("read password err: %v", err)
 	}
 	password := string(bytePassword)
-	fmt.Println()
+	fmt.Fprintln(os.Stderr)
 	if needNotice {
 		fmt.Println("- You must BACKUP your key file! Without the key, it's impossible to set transaction to mechain!")
 		fmt.Println("- You must REMEMBER your password! Without the password, it's impossible to decrypt the key!")
@@ -487,6 +615,23 @@ type cmdConfig struct {
 	EvmRpcAddr string `toml:"evmRpcAddr"`
 	ChainId    string `toml:"chainId"`
 	Host       string `toml:"host"`
+	// TaskRetention is the retention policy of the tasks, which is applied after each successful task
+	TaskRetention *taskRetentionConfig `toml:"taskRetention"`
+	// the bandwidth limits of the transfers, they are the same as the global flags
+	LimitRate         string `toml:"limitRate"`
+	UploadLimitRate   string `toml:"uploadLimitRate"`
+	DownloadLimitRate string `toml:"downloadLimitRate"`
+	// the time zone and the time format of the shown times, they are the same as the global flags
+	TimeZone   string `toml:"timeZone"`
+	TimeFormat string `toml:"timeFormat"`
+}
+
+// taskRetentionConfig is the retention policy of the tasks in the config file, the tasks matching all the
+// set conditions are pruned, it is the same as the flags of "task prune"
+type taskRetentionConfig struct {
+	OlderThan string `toml:"olderThan"`
+	Status    string `toml:"status"`
+	KeepLast  int    `toml:"keepLast"`
 }
 
 // parseConfigFile decode the config file of TOML format
@@ -532,6 +677,37 @@ func loadConfig(ctx *cli.Context) (*cmdConfig, error) {
 	return content, nil
 }
 
+// readCmdConfig parse the config file set by the --config flag, or the config file in the default path
+func readCmdConfig(ctx *cli.Context) (*cmdConfig, error) {
+	// if user has set config file, parse the file
+	if configFile := ctx.String(configFlag); configFile != "" {
+		return parseConfigFile(configFile)
+	}
+	// if file exist in config default path, read default file.
+	// else generate the default file for user in the default path
+	return loadConfig(ctx)
+}
+
+// loadConfigIfExist parse the config file without generating the default one, it returns nil if there is no config file
+func loadConfigIfExist(ctx *cli.Context) (*cmdConfig, error) {
+	configFile := ctx.String(configFlag)
+	if configFile == "" {
+		homeDir, err := getHomeDir(ctx)
+		if err != nil {
+			return nil, nil
+		}
+		configFile = filepath.Join(homeDir, DefaultConfigPath)
+		if _, err = os.Stat(configFile); err != nil {
+			return nil, nil
+		}
+	}
+	config, err := parseConfigFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	return config, nil
+}
+
 // getConfig parse the config of the client, return rpc address, chainId, host, and evm rpc address
 func getConfig(ctx *cli.Context) (string, string, string, string, error) {
 	rpcAddr := ctx.String(rpcAddrConfigField)
@@ -541,22 +717,9 @@ func getConfig(ctx *cli.Context) (string, string, string, string, error) {
 		return rpcAddr, chainId, ctx.String(hostConfigField), evmRpcAddr, nil
 	}
 
-	configFile := ctx.String("config")
-	var config *cmdConfig
-	var err error
-	if configFile != "" {
-		// if user has set config file, parse the file
-		config, err = parseConfigFile(configFile)
-		if err != nil {
-			return "", "", "", "", err
-		}
-	} else {
-		// if file exist in config default path, read default file.
-		// else generate the default file for user in the default path
-		config, err = loadConfig(ctx)
-		if err != nil {
-			return "", "", "", "", err
-		}
+	config, err := readCmdConfig(ctx)
+	if err != nil {
+		return "", "", "", "", err
 	}
 
 	if config.RpcAddr == "" || config.ChainId == "" || config.EvmRpcAddr == "" {
@@ -668,47 +831,74 @@ func parseFileByArg(ctx *cli.Context, argIndex int) (int64, error) {
 
 type ProgressReader struct {
 	io.Reader
+	// Name is the object name in the progress events
+	Name string
+	// TaskID and Bucket identify the object in the progress events, the task id is empty if the object is not in a task
+	TaskID         string
+	Bucket         string
 	Total          int64
 	Current        int64
 	StartTime      time.Time
 	LastPrinted    time.Time
 	LastPrintedStr string
+	// Silent indicates the upload rate is limited without printing the progress bar, the json events are still emitted
+	Silent  bool
+	started bool
+	done    bool
 }
 
 func (pr *ProgressReader) Read(p []byte) (int, error) {
+	if !pr.started {
+		pr.started = true
+		emitProgressEvent(progressEvent{Event: progressEventStarted, TaskID: pr.TaskID, Bucket: pr.Bucket, Object: pr.Name, Total: pr.Total})
+	}
 	n, err := pr.Reader.Read(p)
 	pr.Current += int64(n)
+	uploadLimiter.Wait(n)
 	pr.printProgress()
 	return n, err
 }
 
 func (pr *ProgressReader) printProgress() {
-	progress := float64(pr.Current) / float64(pr.Total) * 100
 	now := time.Now()
-	elapsed := now.Sub(pr.StartTime)
-	uploadSpeed := float64(pr.Current) / elapsed.Seconds()
-
-	if now.Sub(pr.LastPrinted) >= printRateInterval { // print rate every half second
-		progressStr := fmt.Sprintf("uploading progress: %.2f%% [ %s / %s ], rate: %s    ",
-			progress, getConvertSize(pr.Current), getConvertSize(pr.Total), getConvertRate(uploadSpeed))
-		// Clear current line
-		fmt.Print("\r", strings.Repeat(" ", len(pr.LastPrintedStr)), "\r")
-		// Print new progress
-		fmt.Print(progressStr)
-
+	finished := pr.Current >= pr.Total && !pr.done
+	if now.Sub(pr.LastPrinted) >= printRateInterval || finished { // print rate every half second
+		object := progressEvent{TaskID: pr.TaskID, Bucket: pr.Bucket, Object: pr.Name}
+		pr.LastPrintedStr = printTransferProgress(os.Stdout, "uploading", object, pr.Current, pr.Total, pr.StartTime,
+			pr.LastPrintedStr, pr.Silent)
 		pr.LastPrinted = now
 	}
+	if finished {
+		pr.done = true
+		emitProgressEvent(progressEvent{Event: progressEventDone, TaskID: pr.TaskID, Bucket: pr.Bucket, Object: pr.Name,
+			Bytes: pr.Current, Total: pr.Total})
+	}
 }
 
 type ProgressWriter struct {
 	io.Writer
-	Total       int64
-	Current     int64
-	StartTime   time.Time
-	LastPrinted time.Time
+	// Name is the object name in the progress events
+	Name string
+	// TaskID and Bucket identify the object in the progress events, the task id is empty if the object is not in a task
+	TaskID         string
+	Bucket         string
+	Total          int64
+	Current        int64
+	StartTime      time.Time
+	LastPrinted    time.Time
+	LastPrintedStr string
+	// Out is where the progress is printed, the default is stdout
+	Out     io.Writer
+	started bool
+	done    bool
 }
 
 func (pw *ProgressWriter) Write(p []byte) (int, error) {
+	if !pw.started {
+		pw.started = true
+		emitProgressEvent(progressEvent{Event: progressEventStarted, TaskID: pw.TaskID, Bucket: pw.Bucket, Object: pw.Name, Total: pw.Total})
+	}
+	downloadLimiter.Wait(len(p))
 	n, err := pw.Writer.Write(p)
 	pw.Current += int64(n)
 	pw.printProgress()
@@ -716,17 +906,64 @@ func (pw *ProgressWriter) Write(p []byte) (int, error) {
 }
 
 func (pw *ProgressWriter) printProgress() {
-	progress := float64(pw.Current) / float64(pw.Total) * 100
 	now := time.Now()
+	finished := pw.Current >= pw.Total && !pw.done
+	if now.Sub(pw.LastPrinted) >= printRateInterval || finished { // print rate every half second
+		out := pw.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		object := progressEvent{TaskID: pw.TaskID, Bucket: pw.Bucket, Object: pw.Name}
+		pw.LastPrintedStr = printTransferProgress(out, "downloading", object, pw.Current, pw.Total, pw.StartTime,
+			pw.LastPrintedStr, false)
+		pw.LastPrinted = now
+	}
+	if finished {
+		pw.done = true
+		emitProgressEvent(progressEvent{Event: progressEventDone, TaskID: pw.TaskID, Bucket: pw.Bucket, Object: pw.Name,
+			Bytes: pw.Current, Total: pw.Total})
+	}
+}
 
-	elapsed := now.Sub(pw.StartTime)
-	downloadedBytes := pw.Current
-	downloadSpeed := float64(downloadedBytes) / elapsed.Seconds()
+// transferResult records the result of one file in a command which transfers multiple files
+type transferResult struct {
+	Name    string
+	Path    string
+	Size    int64
+	Status  string
+	Comment string
+}
 
-	if now.Sub(pw.LastPrinted) >= printRateInterval { // print rate every half second
-		fmt.Printf("\rdownloding progress: %.2f%% [ %s / %s ], rate: %s    ",
-			progress, getConvertSize(pw.Current), getConvertSize(pw.Total), getConvertRate(downloadSpeed))
-		pw.LastPrinted = now
+func (r *transferResult) fail(err error) {
+	r.Status = transferStatusFailed
+	r.Comment = err.Error()
+	emitObjectEvent(progressEventFailed, "", "", r.Name, err)
+}
+
+// printTransferSummary print the count of the results by status and the detail of the results which are not successful
+func printTransferSummary(results []*transferResult) {
+	counts := make(map[string]int)
+	var totalSize int64
+	for _, result := range results {
+		counts[result.Status]++
+		if result.Status == transferStatusSuccess {
+			totalSize += result.Size
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("summary: %d %s (%s), %d %s, %d %s\n", counts[transferStatusSuccess], transferStatusSuccess, getConvertSize(totalSize),
+		counts[transferStatusSkipped], transferStatusSkipped, counts[transferStatusFailed], transferStatusFailed)
+	if counts[transferStatusSkipped]+counts[transferStatusFailed] == 0 {
+		return
+	}
+
+	format := "%-10s %-50s %s\n"
+	fmt.Printf(format, "status", "name", "reason")
+	for _, result := range results {
+		if result.Status != transferStatusSuccess {
+			fmt.Printf(format, result.Status, result.Name, result.Comment)
+		}
 	}
 }
 
@@ -744,6 +981,53 @@ func getConvertSize(fileSize int64) string {
 	return convertedSize
 }
 
+// parseSize parse the size such as 512K, 20M, 1G or 2T in bytes, the units are multiples of 1024
+// and the optional "B" suffix is allowed, such as 20MB or 20MiB. The empty value is 0
+func parseSize(value string) (int64, error) {
+	size := strings.ToUpper(strings.TrimSpace(value))
+	size = strings.TrimSuffix(size, "B")
+	size = strings.TrimSuffix(size, "I")
+	if size == "" {
+		return 0, nil
+	}
+
+	unit := int64(1)
+	switch size[len(size)-1] {
+	case 'K':
+		unit = 1 << 10
+	case 'M':
+		unit = 1 << 20
+	case 'G':
+		unit = 1 << 30
+	case 'T':
+		unit = 1 << 40
+	}
+	if unit != 1 {
+		size = size[:len(size)-1]
+	}
+	num, err := strconv.ParseFloat(size, 64)
+	if err != nil || num < 0 {
+		return 0, fmt.Errorf("invalid size %s, it should be like 512K, 20M or 1G", value)
+	}
+	return int64(num * float64(unit)), nil
+}
+
+// parseDuration parse the duration such as 36h or 90m, the unit "d" of days is supported as well, such as 7d
+func parseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		num, err := strconv.ParseFloat(days, 64)
+		if err != nil || num < 0 {
+			return 0, fmt.Errorf("invalid duration %s", value)
+		}
+		return time.Duration(num * float64(24*time.Hour)), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %s", value)
+	}
+	return duration, nil
+}
+
 func getConvertRate(rate float64) string {
 	const (
 		KB = 1024
@@ -816,6 +1100,38 @@ func createAndWriteFile(fileName string, content []byte) error {
 	return nil
 }
 
+// writeFileAtomic write the content to a temp file in the same directory and rename it to the file name,
+// so that the file is either the old version or the new one if the process crashes while writing
+func writeFileAtomic(fileName string, content []byte) error {
+	dir := filepath.Dir(fileName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %v", dir, err)
+	}
+
+	tempFile, err := os.CreateTemp(dir, "."+filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempFilePath := tempFile.Name()
+	if _, err = tempFile.Write(content); err == nil {
+		err = tempFile.Sync()
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFilePath, 0o600)
+	}
+	if err == nil {
+		err = os.Rename(tempFilePath, fileName)
+	}
+	if err != nil {
+		_ = os.Remove(tempFilePath)
+		return fmt.Errorf("failed to write the file %s: %v", fileName, err)
+	}
+	return nil
+}
+
 func readFile(fileName string) ([]byte, error) {
 	content, err := os.ReadFile(fileName)
 	if err != nil {
@@ -833,16 +1149,47 @@ type UploadFlag struct {
 	Visibility  storageTypes.VisibilityType `json:"visibility"`
 }
 
+// WatchFlag indicates the options of the watch task
+type WatchFlag struct {
+	// Settle is the duration the file should stay unchanged before it is uploaded
+	Settle   time.Duration `json:"settle"`
+	Interval time.Duration `json:"interval"`
+	Retries  int           `json:"retries"`
+	Parallel int           `json:"parallel"`
+}
+
+// DownloadFlag indicates the download options of the download task
+type DownloadFlag struct {
+	PartSize   uint64 `json:"part_size"`
+	Resumable  bool   `json:"resumable"`
+	SkipVerify bool   `json:"skip_verify"`
+}
+
 type TaskState struct {
-	Lock        *sync.Mutex
+	Lock        *sync.Mutex               `json:"-"`
 	ObjectState map[int]*UploadTaskObject `json:"object_state"`
 	TaskID      string                    `json:"task_id"`
-	Status      string                    `json:"status"`
-	BucketName  string                    `json:"bucket_name"`
-	FolderName  string                    `json:"folder_name"`
-	Flag        UploadFlag                `json:"flag"`
+	// Kind indicates the task uploads, downloads or deletes the objects, the task without kind is an upload task
+	Kind         string       `json:"kind,omitempty"`
+	Status       string       `json:"status"`
+	BucketName   string       `json:"bucket_name"`
+	FolderName   string       `json:"folder_name"`
+	Prefix       string       `json:"prefix,omitempty"`
+	Flag         UploadFlag   `json:"flag"`
+	DownloadFlag DownloadFlag `json:"download_flag"`
+	WatchFlag    WatchFlag    `json:"watch_flag"`
+	CreateTime   int64        `json:"create_time"`
+	// FilterFlag and RecordHash indicate how the upload task is built from the folder, the task is re-planned with them
+	FilterFlag FilterFlag `json:"filter_flag"`
+	RecordHash bool       `json:"record_hash,omitempty"`
+	// journal is the append-only file recording the status transitions while the task is running
+	journal *os.File
+	// dirLocked indicates the task directory is locked by the caller before the task runs, such as the retry which
+	// holds the lock from loading the state until the task finishes
+	dirLocked bool
 }
 
+// UploadTaskObject indicates an object of the task, it is used by the download and delete tasks as well
 type UploadTaskObject struct {
 	BucketName         string `json:"bucket_name"`
 	ObjectName         string `json:"object_name"`
@@ -851,6 +1198,17 @@ type UploadTaskObject struct {
 	ObjectSize         int64  `json:"object_size"`
 	Status             string `json:"status"`
 	Comment            string `json:"comment"`
+	// ModTime and FileHash are recorded when the upload task is built to detect the changes of the file on retry
+	ModTime  int64  `json:"mod_time,omitempty"`
+	FileHash string `json:"file_hash,omitempty"`
+	// StartTime, EndTime and SealTime are the unix milliseconds of the latest run of the object,
+	// RetryCount is the number of the runs after the first one
+	StartTime  int64  `json:"start_time,omitempty"`
+	EndTime    int64  `json:"end_time,omitempty"`
+	SealTime   int64  `json:"seal_time,omitempty"`
+	Bytes      int64  `json:"bytes,omitempty"`
+	TxHash     string `json:"tx_hash,omitempty"`
+	RetryCount int    `json:"retry_count,omitempty"`
 }
 
 func (t *TaskState) UpdateObjectState(index int, status, comment string) {
@@ -859,6 +1217,100 @@ func (t *TaskState) UpdateObjectState(index int, status, comment string) {
 		t.Lock.Lock()
 		t.ObjectState[index].Status = status
 		t.ObjectState[index].Comment = comment
+		t.appendJournal(index, status, comment)
 		t.Lock.Unlock()
 	}
 }
+
+// AddObject append the object to the task while the task is running, it returns the index of the object
+func (t *TaskState) AddObject(object *UploadTaskObject) int {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	index := len(t.ObjectState)
+	t.ObjectState[index] = object
+	return index
+}
+
+// StartObject record the start time of the object run, the run is counted as a retry if the object has been run before
+func (t *TaskState) StartObject(index int) {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	object, ok := t.ObjectState[index]
+	if !ok {
+		return
+	}
+	if object.StartTime != 0 {
+		object.RetryCount++
+	}
+	object.StartTime = time.Now().UnixMilli()
+	object.EndTime, object.SealTime, object.Bytes = 0, 0, 0
+}
+
+// FinishObject record the end time, the transferred bytes and the transaction hash of the object run
+func (t *TaskState) FinishObject(index int, txnHash string, bytes int64) {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	object, ok := t.ObjectState[index]
+	if !ok {
+		return
+	}
+	object.EndTime = time.Now().UnixMilli()
+	object.Bytes = bytes
+	if txnHash != "" {
+		object.TxHash = txnHash
+	}
+}
+
+// SealObject record the time when the object is found sealed
+func (t *TaskState) SealObject(index int) {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	if object, ok := t.ObjectState[index]; ok {
+		object.SealTime = time.Now().UnixMilli()
+	}
+}
+
+// GetObjectStatus return the status of the task object, it is safe to call while the objects are being uploaded
+func (t *TaskState) GetObjectStatus(index int) string {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	if object, ok := t.ObjectState[index]; ok {
+		return object.Status
+	}
+	return ""
+}
+
+// GetKind return the kind of the task
+func (t *TaskState) GetKind() string {
+	if t.Kind == "" {
+		return TaskKindUpload
+	}
+	return t.Kind
+}
+
+// CountObjectStatus return the number of the task objects in every status
+func (t *TaskState) CountObjectStatus() map[string]int {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	counts := make(map[string]int)
+	for _, object := range t.ObjectState {
+		counts[object.Status]++
+	}
+	return counts
+}
+
+// SetStatus update the overall status of the task
+func (t *TaskState) SetStatus(status string) {
+	t.Lock.Lock()
+	t.Status = status
+	t.appendJournal(taskJournalStatusIndex, status, "")
+	t.Lock.Unlock()
+}
+
+// Marshal encode the task state in json format while holding the lock,
+// so that the content is consistent with the concurrent updates
+func (t *TaskState) Marshal() ([]byte, error) {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	return json.Marshal(t)
+}